@@ -3,68 +3,196 @@ package annotation
 import (
 	"fmt"
 	"github.com/mitchellh/mapstructure"
+	"go/token"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Annotation struct {
+	Name     string                 // Name of the annotation
+	Value    string                 // Arguments exactly as written between the parentheses
+	Map      map[string]interface{} // Arguments by name
+	Args     []Arg                  // Arguments in the order they were written
+	Position token.Position         // Where the annotation starts in the source
+}
+
+// Arg is a single annotation argument, in the order it was written.
+// Positional arguments have an empty Name.
+type Arg struct {
 	Name  string
-	Value string
-	Map   map[string]interface{}
+	Value interface{}
 }
 
+// valueKey is the Map key holding the argument of single-argument
+// annotations such as @Inject(FooService).
+const valueKey = "value"
+
 func NewAnnotation(name string, value string) Annotation {
-	mp := asMap(value)
-	v := valueMap(mp)
-	return Annotation{Name: name, Map: mp, Value: v}
+	return newAnnotation(name, value, asArgs(value))
+}
+
+func newAnnotation(name string, value string, args []Arg) Annotation {
+	return Annotation{Name: name, Map: asMap(args), Value: strings.TrimSpace(value), Args: args}
 }
 
+// RawValue returns the arguments of the annotation exactly as written.
 func (m *Annotation) RawValue() string {
 	return m.Value
 }
 
+// Positional returns the positional arguments in the order they were written.
+func (m *Annotation) Positional() []interface{} {
+	var values []interface{}
+	for _, arg := range m.Args {
+		if arg.Name == "" {
+			values = append(values, arg.Value)
+		}
+	}
+	return values
+}
+
+// Decode fills the struct pointed by a with the annotation arguments. Fields
+// are matched by the attr tag; the pos option, as in `attr:",pos=0"`, maps a
+// positional argument into the field.
 func (m *Annotation) Decode(a interface{}) error {
 	config := &mapstructure.DecoderConfig{
-		TagName: "attr",
-		Result:  &a,
+		TagName:    "attr",
+		Result:     a,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(decodeHook, mapstructure.StringToTimeDurationHookFunc()),
 	}
 	decoder, err := mapstructure.NewDecoder(config)
 	if err != nil {
 		return err
 	}
-	return decoder.Decode(m.Map)
+	return decoder.Decode(*m)
 }
 
-func valueMap(mp map[string]interface{}) string {
-	var entries []string
-	for k, v := range mp {
-		entries = append(entries, strings.Join([]string{k, fmt.Sprintf("%v", v)}, "="))
+// decodeHook converts annotations, including the ones nested as argument
+// values, into the attribute map expected by the target type, placing
+// positional arguments under their field keys.
+func decodeHook(_ reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if ref, ok := data.(TypeRef); ok && to.Kind() == reflect.String {
+		return ref.String(), nil
 	}
-	return strings.Join(entries, ",")
+
+	an, ok := data.(Annotation)
+	if !ok || to == reflect.TypeOf(an) {
+		return data, nil
+	}
+
+	mp := make(map[string]interface{}, len(an.Map))
+	for k, v := range an.Map {
+		mp[k] = v
+	}
+
+	for to.Kind() == reflect.Ptr {
+		to = to.Elem()
+	}
+	if to.Kind() != reflect.Struct {
+		return mp, nil
+	}
+
+	positional := an.Positional()
+	for i := 0; i < to.NumField(); i++ {
+		field := to.Field(i)
+		tag := strings.Split(field.Tag.Get("attr"), ",")
+		for _, opt := range tag[1:] {
+			if !strings.HasPrefix(opt, "pos=") {
+				continue
+			}
+			pos, err := strconv.Atoi(strings.TrimPrefix(opt, "pos="))
+			if err != nil {
+				return nil, fmt.Errorf("invalid pos option on field %s: %s", field.Name, opt)
+			}
+			if pos < 0 || pos >= len(positional) {
+				continue
+			}
+			key := tag[0]
+			if key == "" {
+				key = field.Name
+			}
+			mp[key] = positional[pos]
+		}
+	}
+
+	return mp, nil
+}
+
+func asArgs(value string) []Arg {
+	args, err := parseArgs(value)
+	if err != nil {
+		log.Warnf("the annotation arguments could not be parsed. %s", err.Error())
+	}
+	return args
 }
 
-func asMap(value string) map[string]interface{} {
-	entries := strings.Split(value, ",")
+func asMap(args []Arg) map[string]interface{} {
 	mp := make(map[string]interface{})
-	for _, entry := range entries {
-		entry = strings.TrimSpace(entry)
-		e := strings.Split(entry, "=")
-		if len(e) == 2 {
-			mp[strings.ReplaceAll(strings.TrimSpace(e[0]), " ", "")] = determineType(strings.TrimSpace(e[1]))
+	var positional []interface{}
+	for _, arg := range args {
+		if arg.Name == "" {
+			positional = append(positional, arg.Value)
+			continue
 		}
+		mp[arg.Name] = arg.Value
+	}
+	if _, ok := mp[valueKey]; !ok && len(positional) == 1 {
+		mp[valueKey] = positional[0]
 	}
 	return mp
 }
 
+// determineType converts an unquoted value into a Go value:
+//
+//   - true and false are booleans;
+//   - null is nil;
+//   - integers, such as 42, -7, 0x1F, 0o17 or 0b101, are int;
+//   - decimal numbers, such as 1.0, -2.5 or 1e3, are float64;
+//   - durations, such as 5s, -1.5h or 1h30m, are time.Duration;
+//   - anything else is a string.
+//
+// Quoted values are never converted and are always strings.
 func determineType(value string) interface{} {
-	if intValue, err := strconv.Atoi(value); err == nil {
-		return intValue
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
 	}
-	if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+	if !isNumeric(value) {
+		return value
+	}
+	if intValue, err := strconv.ParseInt(value, intBase(value), 0); err == nil {
+		return int(intValue)
+	}
+	if floatValue, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xX_") {
 		return floatValue
 	}
-	if boolValue, err := strconv.ParseBool(value); err == nil {
-		return boolValue
+	if durationValue, err := time.ParseDuration(value); err == nil {
+		return durationValue
 	}
 	return value
 }
+
+// isNumeric reports whether the value starts like a number, with an
+// optional sign followed by a digit or a decimal point.
+func isNumeric(value string) bool {
+	value = strings.TrimLeft(value, "+-")
+	return value != "" && (value[0] == '.' || ('0' <= value[0] && value[0] <= '9'))
+}
+
+// intBase returns 0, letting strconv detect the base, for integers with a
+// 0x, 0o or 0b prefix, and 10 otherwise so leading zeros stay decimal.
+func intBase(value string) int {
+	value = strings.ToLower(strings.TrimLeft(value, "+-"))
+	for _, prefix := range []string{"0x", "0o", "0b"} {
+		if strings.HasPrefix(value, prefix) {
+			return 0
+		}
+	}
+	return 10
+}
@@ -0,0 +1,242 @@
+package annotation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewAnnotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]interface{}
+	}{
+		{"Test1", "code=201, type=pkg.Response", map[string]interface{}{"code": 201, "type": "pkg.Response"}},
+		{"Test2", `description="foo, bar (baz)"`, map[string]interface{}{"description": "foo, bar (baz)"}},
+		{"Test3", "path=/a?x=1, method=GET", map[string]interface{}{"path": "/a?x=1", "method": "GET"}},
+		{"Test4", `description='it\'s = "quoted"'`, map[string]interface{}{"description": `it's = "quoted"`}},
+		{"Test5", "description=`raw \\n, (text)`", map[string]interface{}{"description": `raw \n, (text)`}},
+		{"Test6", `description="line\nbreak é"`, map[string]interface{}{"description": "line\nbreak é"}},
+		{"Test7", `code="201"`, map[string]interface{}{"code": "201"}},
+		{"Test8", "", map[string]interface{}{}},
+		{"Test9", "consumes=[application/json, application/yaml]", map[string]interface{}{"consumes": []interface{}{"application/json", "application/yaml"}}},
+		{"Test10", `codes=[200, 404], names=["a, b", 'c'], empty=[]`, map[string]interface{}{"codes": []interface{}{200, 404}, "names": []interface{}{"a, b", "c"}, "empty": []interface{}{}}},
+		{"Test11", "matrix=[[1, 2], [3]]", map[string]interface{}{"matrix": []interface{}{[]interface{}{1, 2}, []interface{}{3}}}},
+		{"Test12", "consumes=[application/json", map[string]interface{}{}},
+		{"Test13", `defaults={timeout: 5s, retries: 3, "x-id": 'a, b', url: http://x/y}`, map[string]interface{}{"defaults": map[string]interface{}{"timeout": 5 * time.Second, "retries": 3, "x-id": "a, b", "url": "http://x/y"}}},
+		{"Test14", "defaults={limits: {max: 10}, tags: [a, b]}, empty={}", map[string]interface{}{"defaults": map[string]interface{}{"limits": map[string]interface{}{"max": 10}, "tags": []interface{}{"a", "b"}}, "empty": map[string]interface{}{}}},
+		{"Test15", "defaults={timeout 5s}", map[string]interface{}{}},
+		{"Test16", "types=[[]pkg.Resp, *pkg.Err], type=[]int", map[string]interface{}{"types": []interface{}{"[]pkg.Resp", "*pkg.Err"}, "type": "[]int"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAnnotation("Test", tt.value); !reflect.DeepEqual(got.Map, tt.expected) {
+				t.Errorf("NewAnnotation().Map = %v, want %v", got.Map, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewAnnotation_positional(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []Arg
+		mp       map[string]interface{}
+	}{
+		{"Test1", "FooService", []Arg{{Value: "FooService"}}, map[string]interface{}{"value": "FooService"}},
+		{"Test2", `"/users"`, []Arg{{Value: "/users"}}, map[string]interface{}{"value": "/users"}},
+		{"Test3", `"/users", method=GET`, []Arg{{Value: "/users"}, {Name: "method", Value: "GET"}}, map[string]interface{}{"value": "/users", "method": "GET"}},
+		{"Test4", "a, b", []Arg{{Value: "a"}, {Value: "b"}}, map[string]interface{}{}},
+		{"Test5", "/a?x=1", []Arg{{Value: "/a?x=1"}}, map[string]interface{}{"value": "/a?x=1"}},
+		{"Test6", "x, value=y", []Arg{{Value: "x"}, {Name: "value", Value: "y"}}, map[string]interface{}{"value": "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAnnotation("Test", tt.value)
+			if !reflect.DeepEqual(got.Args, tt.expected) {
+				t.Errorf("NewAnnotation().Args = %v, want %v", got.Args, tt.expected)
+			}
+			if !reflect.DeepEqual(got.Map, tt.mp) {
+				t.Errorf("NewAnnotation().Map = %v, want %v", got.Map, tt.mp)
+			}
+		})
+	}
+}
+
+func TestAnnotation_Decode(t *testing.T) {
+	type path struct {
+		Path   string `attr:",pos=0"`
+		Method string `attr:"method"`
+		Code   int    `attr:"code,pos=1"`
+	}
+
+	an := NewAnnotation("Path", `"/users", 201, method=GET`)
+
+	var got path
+	if err := an.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := path{Path: "/users", Method: "GET", Code: 201}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %v, want %v", got, expected)
+	}
+}
+
+func TestAnnotation_Decode_slices(t *testing.T) {
+	type consume struct {
+		Types []string `attr:"types"`
+		Codes []int    `attr:"codes"`
+	}
+
+	an := NewAnnotation("RestConsume", "types=[application/json, application/yaml], codes=[200, 201]")
+
+	var got consume
+	if err := an.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := consume{Types: []string{"application/json", "application/yaml"}, Codes: []int{200, 201}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %v, want %v", got, expected)
+	}
+}
+
+func TestNewAnnotation_nested(t *testing.T) {
+	an := NewAnnotation("RestEndpoint", "path=/foo, responses=[@Response(code=200, type=pkg.Resp), @Response(code=404)], auth=@Basic")
+
+	responses, ok := an.Map["responses"].([]interface{})
+	if !ok || len(responses) != 2 {
+		t.Fatalf("NewAnnotation().Map[responses] = %v, want two annotations", an.Map["responses"])
+	}
+
+	ok200, _ := responses[0].(Annotation)
+	if ok200.Name != "Response" || !reflect.DeepEqual(ok200.Map, map[string]interface{}{"code": 200, "type": "pkg.Resp"}) {
+		t.Errorf("NewAnnotation().Map[responses][0] = %v", responses[0])
+	}
+
+	if auth, _ := an.Map["auth"].(Annotation); auth.Name != "Basic" {
+		t.Errorf("NewAnnotation().Map[auth] = %v", an.Map["auth"])
+	}
+}
+
+func TestAnnotation_Decode_nested(t *testing.T) {
+	type header struct {
+		Name string `attr:",pos=0"`
+	}
+	type response struct {
+		Code    int      `attr:"code"`
+		Type    string   `attr:"type"`
+		Headers []header `attr:"headers"`
+	}
+	type endpoint struct {
+		Path      string     `attr:"path"`
+		Default   *response  `attr:"default"`
+		Responses []response `attr:"responses"`
+		Raw       Annotation `attr:"default"`
+	}
+
+	an := NewAnnotation("RestEndpoint", `path=/foo, default=@Response(code=500), responses=[@Response(code=200, type=pkg.Resp, headers=[@Header("X-Id")]), @Response(code=404)]`)
+
+	var got endpoint
+	if err := an.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := endpoint{
+		Path:    "/foo",
+		Default: &response{Code: 500},
+		Responses: []response{
+			{Code: 200, Type: "pkg.Resp", Headers: []header{{Name: "X-Id"}}},
+			{Code: 404},
+		},
+		Raw: an.Map["default"].(Annotation),
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestAnnotation_Decode_maps(t *testing.T) {
+	type limits struct {
+		Max int `attr:"max"`
+	}
+	type config struct {
+		Labels map[string]string `attr:"labels"`
+		Limits limits            `attr:"limits"`
+	}
+
+	an := NewAnnotation("Config", "labels={app: orders, team: core}, limits={max: 10}")
+
+	var got config
+	if err := an.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := config{Labels: map[string]string{"app": "orders", "team": "core"}, Limits: limits{Max: 10}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %v, want %v", got, expected)
+	}
+}
+
+func TestDetermineType(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{"Test1", "201", 201},
+		{"Test2", "-7", -7},
+		{"Test3", "0x1F", 31},
+		{"Test4", "0o17", 15},
+		{"Test5", "0b101", 5},
+		{"Test6", "007", 7},
+		{"Test7", "1.0", 1.0},
+		{"Test8", "-2.5e3", -2500.0},
+		{"Test9", "true", true},
+		{"Test10", "false", false},
+		{"Test11", "t", "t"},
+		{"Test12", "F", "F"},
+		{"Test13", "null", nil},
+		{"Test14", "5s", 5 * time.Second},
+		{"Test15", "1h30m", 90 * time.Minute},
+		{"Test16", "-1.5h", -90 * time.Minute},
+		{"Test17", "NaN", "NaN"},
+		{"Test18", "1.2.3", "1.2.3"},
+		{"Test19", "application/json", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := determineType(tt.value); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("determineType() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnnotation_Decode_literals(t *testing.T) {
+	type config struct {
+		Timeout  time.Duration `attr:"timeout"`
+		Interval time.Duration `attr:"interval"`
+		Version  string        `attr:"version"`
+		Flag     string        `attr:"flag"`
+		Mask     int           `attr:"mask"`
+	}
+
+	an := NewAnnotation("Config", `timeout=5s, interval="1m", version="1.0", flag='true', mask=0xFF`)
+
+	var got config
+	if err := an.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	expected := config{Timeout: 5 * time.Second, Interval: time.Minute, Version: "1.0", Flag: "true", Mask: 255}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %v, want %v", got, expected)
+	}
+}
@@ -2,6 +2,7 @@ package annotation
 
 import (
 	"errors"
+	"go/ast"
 	"golang.org/x/tools/go/packages"
 	"path/filepath"
 )
@@ -9,6 +10,9 @@ import (
 func Collect(options ...Option) (*Collector, error) {
 	c := &Collector{
 		pkgProcessed: make(map[string]bool),
+		typeRefs:     make(map[string][]string),
+		loaded:       make(map[string]*packages.Package),
+		parsed:       make(map[*ast.CommentGroup][]Annotation),
 		pkgConfig: &packages.Config{
 			Mode: packages.NeedName | packages.NeedTypesInfo | packages.NeedSyntax |
 				packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes |
@@ -32,6 +36,12 @@ func Collect(options ...Option) (*Collector, error) {
 		return nil, err
 	}
 
+	c.resolveTypeRefs()
+
+	if c.strict && len(c.diagnostics) > 0 {
+		return nil, c.diagnostics
+	}
+
 	return c, nil
 }
 
@@ -59,6 +69,40 @@ func WithPackages(pkgs ...string) Option {
 	}
 }
 
+// WithStrict makes Collect fail with Diagnostics, listing every malformed
+// annotation, instead of ignoring them.
+func WithStrict() Option {
+	return func(c *Collector) error {
+		c.strict = true
+		return nil
+	}
+}
+
+// WithTypeRefs marks attributes of an annotation as references to Go types,
+// such as type=github.com/foo/pkg.Response. Their values are resolved into
+// a TypeRef; the ones that can't be resolved are reported as Diagnostics.
+func WithTypeRefs(annotation string, attrs ...string) Option {
+	return func(c *Collector) error {
+		if annotation == "" || len(attrs) == 0 {
+			return errors.New("no type reference informed")
+		}
+		c.typeRefs[annotation] = append(c.typeRefs[annotation], attrs...)
+		return nil
+	}
+}
+
+// WithSyntax sets how annotations are marked in comments. By default they
+// are written as "// @Name(key=value)".
+func WithSyntax(syntax Syntax) Option {
+	return func(c *Collector) error {
+		if syntax.Grammar != AnnotationGrammar && syntax.Grammar != MarkerGrammar {
+			return errors.New("unknown annotation grammar")
+		}
+		c.syntax = syntax
+		return nil
+	}
+}
+
 func WithPath(path string) Option {
 	return func(c *Collector) error {
 		if path == "" {
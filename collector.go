@@ -1,10 +1,15 @@
 package annotation
 
 import (
+	"fmt"
 	"go/ast"
+	"go/doc"
+	"go/doc/comment"
 	"go/token"
 	"go/types"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -13,18 +18,30 @@ import (
 type filterFunc func(Entry) bool
 
 type Collector struct {
+	syntax       Syntax
+	strict       bool
 	filters      []string
 	pkgs         []string
 	basePath     string
 	pkgProcessed map[string]bool
 	pkgConfig    *packages.Config
 	entries      []Entry
+	diagnostics  Diagnostics
+	typeRefs     map[string][]string
+	loaded       map[string]*packages.Package
+	parsed       map[*ast.CommentGroup][]Annotation
 }
 
 func (c *Collector) Entries() []Entry {
 	return c.entries
 }
 
+// Diagnostics returns the problems found in the annotations, such as the
+// malformed ones that were ignored.
+func (c *Collector) Diagnostics() Diagnostics {
+	return c.diagnostics
+}
+
 func (c *Collector) filterEntries(filter filterFunc) (entries []Entry) {
 	for _, entry := range c.Entries() {
 		if filter(entry) {
@@ -37,7 +54,7 @@ func (c *Collector) filterEntries(filter filterFunc) (entries []Entry) {
 func (c *Collector) EntriesWithArgType(annotationName string, argumentType string) []Entry {
 	return c.filterEntries(func(entry Entry) bool {
 		return !entry.IsStruct() &&
-			containsParamOfType(entry.Func.Parameters, argumentType) &&
+			containsParamOfType(entry.Func, argumentType) &&
 			containsAnnotation(entry.Annotations, annotationName)
 	})
 }
@@ -56,6 +73,49 @@ func (c *Collector) EntriesWith(annotation string) []Entry {
 	})
 }
 
+// EntriesWithField returns the struct entries having at least one field with
+// the annotation.
+func (c *Collector) EntriesWithField(annotation string) []Entry {
+	return c.filterEntries(func(entry Entry) bool {
+		for _, field := range entry.Fields {
+			if containsAnnotation(field.Annotations, annotation) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// PackageEntries returns the entries of the annotated packages, whose
+// annotations are merged from the package doc comments of all their files.
+func (c *Collector) PackageEntries() []Entry {
+	return c.EntriesOfKind(PackageKind)
+}
+
+// PackageOf returns the entry of the annotated package of the entry.
+func (c *Collector) PackageOf(entry Entry) (Entry, bool) {
+	for _, pkg := range c.PackageEntries() {
+		if pkg.Path == entry.Path {
+			return pkg, true
+		}
+	}
+	return Entry{}, false
+}
+
+// EntriesOfKind returns the entries of any of the kinds.
+func (c *Collector) EntriesOfKind(kinds ...Kind) []Entry {
+	return c.filterEntries(func(entry Entry) bool {
+		return entry.Kind.In(kinds...)
+	})
+}
+
+// EntriesWithKind returns the entries of the kind having the annotation.
+func (c *Collector) EntriesWithKind(annotation string, kind Kind) []Entry {
+	return c.filterEntries(func(entry Entry) bool {
+		return entry.Kind == kind && containsAnnotation(entry.Annotations, annotation)
+	})
+}
+
 func (c *Collector) EntriesWithPrefix(prefix string) []Entry {
 	return c.filterEntries(func(entry Entry) bool {
 		for _, ann := range entry.Annotations {
@@ -112,6 +172,10 @@ func (c *Collector) load(value string) (err error) {
 
 	c.pkgProcessed[value] = true
 
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		c.loaded[p.PkgPath] = p
+	})
+
 	for _, p := range pkgs {
 
 		if p.Module == nil {
@@ -141,9 +205,14 @@ func (c *Collector) load(value string) (err error) {
 	return nil
 }
 
-func containsParamOfType(parameters []EntryFuncType, paramType string) bool {
-	for _, parameter := range parameters {
-		if parameter.Type == paramType {
+// containsParamOfType reports whether a parameter of the function is of the
+// type, or is of a type parameter constrained by it.
+func containsParamOfType(fn EntryFunc, paramType string) bool {
+	for _, parameter := range fn.Parameters {
+		if parameter.Type == paramType || parameter.QualifiedType == paramType {
+			return true
+		}
+		if parameter.TypeParam && (parameter.Constraint == paramType || fn.constraintOf(parameter.Type) == paramType) {
 			return true
 		}
 	}
@@ -161,19 +230,42 @@ func containsAnnotation(annotations []Annotation, annoName string) bool {
 	return false
 }
 
-func getStructInfos(file *ast.File) (entries []Entry) {
+// getStructInfos returns the types declared in the file, each followed by its
+// methods, which may be declared in any of the files of the package.
+func getStructInfos(file *ast.File, files []*ast.File, ifaces *interfaceIndex) (entries []Entry) {
 
 	for _, decl := range file.Decls {
 
 		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
 			for _, spec := range genDecl.Specs {
 				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					if ifaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						entries = append(entries, Entry{
+							Kind:       InterfaceKind,
+							Interface:  typeSpec.Name.Name,
+							Embeds:     getInterfaceEmbeds(ifaceType),
+							TypeParams: getTypeParams(typeSpec.TypeParams),
+							Comments:   getComments(genDecl.Doc),
+							node:       declNode(genDecl, typeSpec),
+							docs:       []*ast.CommentGroup{genDecl.Doc},
+						})
+						entries = append(entries, getInterfaceMethods(typeSpec.Name.Name, interfaceDecl{ifaceType, file, ifaces.root}, ifaces, map[*ast.InterfaceType]bool{})...)
+						continue
+					}
 					structInfo := Entry{
-						Struct:   typeSpec.Name.Name,
-						Comments: getComments(genDecl.Doc),
+						Kind:       typeKind(typeSpec),
+						Struct:     typeSpec.Name.Name,
+						Fields:     getStructFields(typeSpec),
+						TypeParams: getTypeParams(typeSpec.TypeParams),
+						Comments:   getComments(genDecl.Doc),
+						node:       declNode(genDecl, typeSpec),
+						docs:       []*ast.CommentGroup{genDecl.Doc},
+					}
+					if structInfo.Kind != StructKind {
+						structInfo.Type = types.ExprString(typeSpec.Type)
 					}
 					entries = append(entries, structInfo)
-					entries = append(entries, getStructMethods(file, typeSpec.Name.Name)...)
+					entries = append(entries, getStructMethods(files, typeSpec)...)
 				}
 			}
 		}
@@ -182,35 +274,400 @@ func getStructInfos(file *ast.File) (entries []Entry) {
 	return entries
 }
 
-// getStructMethods returns all the methods associated with the provided struct.
-func getStructMethods(file *ast.File, structName string) (entries []Entry) {
+// getVarInfos returns the constants and variables declared in the file. The
+// comments of a parenthesised declaration apply to each of its specs.
+func getVarInfos(file *ast.File) (entries []Entry) {
 
 	for _, decl := range file.Decls {
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				if starExpr, ok := funcDecl.Recv.List[0].Type.(*ast.StarExpr); ok {
-					if ident, ok := starExpr.X.(*ast.Ident); ok && ident.Name == structName {
-						entries = append(entries, Entry{
-							Struct:   structName,
-							Comments: getComments(funcDecl.Doc),
-							Func: EntryFunc{
-								Name:       funcDecl.Name.Name,
-								Parameters: getFuncParams(funcDecl.Type.Params),
-								Results:    getFuncParams(funcDecl.Type.Results),
-							},
-						})
+
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			var varType string
+			if valueSpec.Type != nil {
+				varType = types.ExprString(valueSpec.Type)
+			}
+
+			docs := []*ast.CommentGroup{genDecl.Doc}
+			if valueSpec.Doc != genDecl.Doc {
+				docs = append(docs, valueSpec.Doc)
+			}
+			docs = append(docs, valueSpec.Comment)
+
+			var comments []string
+			for _, doc := range docs {
+				comments = append(comments, getComments(doc)...)
+			}
+
+			kind := VarKind
+			if genDecl.Tok == token.CONST {
+				kind = ConstKind
+			}
+
+			for _, name := range valueSpec.Names {
+				if name.Name == "_" {
+					continue
+				}
+				entries = append(entries, Entry{
+					Kind: kind,
+					Var: EntryVar{
+						Name:  name.Name,
+						Type:  varType,
+						Const: genDecl.Tok == token.CONST,
+					},
+					Comments: comments,
+					node:     declNode(genDecl, valueSpec),
+					docs:     docs,
+				})
+			}
+		}
+	}
+
+	return entries
+}
+
+// interfaceDecl is an interface type and where it is declared.
+type interfaceDecl struct {
+	iface *ast.InterfaceType
+	file  *ast.File
+	pkg   *packages.Package
+}
+
+// interfaceIndex finds the interfaces declared in a package and in the
+// packages it depends on, whose syntax is loaded along with it.
+type interfaceIndex struct {
+	root *packages.Package
+	pkgs map[*packages.Package]map[string]interfaceDecl
+}
+
+func newInterfaceIndex(root *packages.Package) *interfaceIndex {
+	return &interfaceIndex{root: root, pkgs: make(map[*packages.Package]map[string]interfaceDecl)}
+}
+
+// embedded returns the declaration of the interface embedded as expr by the
+// interface decl, such as Finder, Finder[T] or io.Closer.
+func (x *interfaceIndex) embedded(decl interfaceDecl, expr ast.Expr) (interfaceDecl, bool) {
+
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		expr = e.X
+	case *ast.IndexListExpr:
+		expr = e.X
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		found, ok := x.interfaces(decl.pkg)[e.Name]
+		return found, ok
+	case *ast.SelectorExpr:
+		if decl.pkg.TypesInfo == nil {
+			return interfaceDecl{}, false
+		}
+		obj, ok := decl.pkg.TypesInfo.Uses[e.Sel].(*types.TypeName)
+		if !ok || obj.Pkg() == nil {
+			return interfaceDecl{}, false
+		}
+		pkg := findPackage(decl.pkg, obj.Pkg().Path())
+		if pkg == nil {
+			return interfaceDecl{}, false
+		}
+		found, ok := x.interfaces(pkg)[obj.Name()]
+		return found, ok
+	}
+	return interfaceDecl{}, false
+}
+
+// interfaces returns the interfaces declared in the package, by name.
+func (x *interfaceIndex) interfaces(pkg *packages.Package) map[string]interfaceDecl {
+	if ifaces, ok := x.pkgs[pkg]; ok {
+		return ifaces
+	}
+	ifaces := make(map[string]interfaceDecl)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+				for _, spec := range genDecl.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						if ifaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+							ifaces[typeSpec.Name.Name] = interfaceDecl{ifaceType, file, pkg}
+						}
 					}
 				}
 			}
 		}
 	}
+	x.pkgs[pkg] = ifaces
+	return ifaces
+}
+
+// getInterfaceEmbeds returns the types embedded by the interface, such as
+// io.Reader or the type unions of a constraint.
+func getInterfaceEmbeds(ifaceType *ast.InterfaceType) (embeds []string) {
+	if ifaceType.Methods == nil {
+		return nil
+	}
+	for _, field := range ifaceType.Methods.List {
+		if len(field.Names) == 0 {
+			embeds = append(embeds, types.ExprString(field.Type))
+		}
+	}
+	return embeds
+}
+
+// getInterfaceMethods returns the methods of the interface, including the
+// ones of the interfaces it embeds, declared in its package or in the
+// packages it imports. The methods are attributed to the interface named
+// ifaceName.
+func getInterfaceMethods(ifaceName string, decl interfaceDecl, ifaces *interfaceIndex, seen map[*ast.InterfaceType]bool) (entries []Entry) {
+
+	if decl.iface.Methods == nil {
+		return nil
+	}
+
+	for _, field := range decl.iface.Methods.List {
+
+		if len(field.Names) == 0 {
+			if embedded, ok := ifaces.embedded(decl, field.Type); ok && !seen[embedded.iface] {
+				seen[embedded.iface] = true
+				entries = append(entries, getInterfaceMethods(ifaceName, embedded, ifaces, seen)...)
+			}
+			continue
+		}
+
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+
+		for _, name := range field.Names {
+			entries = append(entries, Entry{
+				Kind:      MethodKind,
+				Interface: ifaceName,
+				Comments:  getComments(field.Doc),
+				node:      field,
+				docs:      []*ast.CommentGroup{field.Doc},
+				src:       commentSource{pkg: decl.pkg, file: decl.file},
+				Func: EntryFunc{
+					Name:       name.Name,
+					Parameters: getFuncParams(funcType.Params),
+					Results:    getFuncParams(funcType.Results),
+					Variadic:   isVariadic(funcType.Params),
+					Named:      hasNamedResults(funcType.Results),
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// getStructFields returns the fields of the struct declared by the spec.
+func getStructFields(typeSpec *ast.TypeSpec) (fields []EntryField) {
+
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok || structType.Fields == nil {
+		return nil
+	}
+
+	for _, field := range structType.Fields.List {
+
+		fieldType := types.ExprString(field.Type)
+		comments := append(getComments(field.Doc), getComments(field.Comment)...)
+
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+
+		if len(field.Names) == 0 {
+			fields = append(fields, EntryField{
+				Name:     baseTypeName(field.Type),
+				Type:     fieldType,
+				Tag:      tag,
+				Embedded: true,
+				Comments: comments,
+				field:    field,
+			})
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields = append(fields, EntryField{
+				Name:     name.Name,
+				Type:     fieldType,
+				Tag:      tag,
+				Comments: comments,
+				field:    field,
+			})
+		}
+	}
+
+	return fields
+}
+
+// declNode returns the node spanning the declaration of a spec: the whole
+// declaration, unless it groups several specs between parentheses.
+func declNode(genDecl *ast.GenDecl, spec ast.Spec) ast.Node {
+	if genDecl.Lparen.IsValid() {
+		return spec
+	}
+	return genDecl
+}
+
+// getStructMethods returns all the methods associated with the provided
+// struct, declared in any of the files of its package.
+func getStructMethods(files []*ast.File, typeSpec *ast.TypeSpec) (entries []Entry) {
+
+	structName := typeSpec.Name.Name
+	typeParams := getTypeParams(typeSpec.TypeParams)
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				if recv, ok := getFuncReceiver(funcDecl); ok && receiverTypeName(funcDecl) == structName {
+					entries = append(entries, Entry{
+						Kind:     MethodKind,
+						Struct:   structName,
+						Comments: getComments(funcDecl.Doc),
+						node:     funcDecl,
+						docs:     []*ast.CommentGroup{funcDecl.Doc},
+						src:      commentSource{file: file},
+						Func: EntryFunc{
+							Name:       funcDecl.Name.Name,
+							Receiver:   recv,
+							TypeParams: receiverTypeParams(funcDecl, typeParams),
+							Parameters: getFuncParams(funcDecl.Type.Params),
+							Results:    getFuncParams(funcDecl.Type.Results),
+							Variadic:   isVariadic(funcDecl.Type.Params),
+							Named:      hasNamedResults(funcDecl.Type.Results),
+						},
+					})
+				}
+			}
+		}
+	}
 
 	return entries
 }
 
+// getFuncReceiver returns the receiver of the method, or false when the
+// declaration is a function.
+func getFuncReceiver(funcDecl *ast.FuncDecl) (EntryReceiver, bool) {
+
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return EntryReceiver{}, false
+	}
+
+	field := funcDecl.Recv.List[0]
+	recv := EntryReceiver{Type: types.ExprString(field.Type)}
+	if len(field.Names) > 0 {
+		recv.Name = field.Names[0].Name
+	}
+
+	expr := field.Type
+	if paren, ok := expr.(*ast.ParenExpr); ok {
+		expr = paren.X
+	}
+	_, recv.Pointer = expr.(*ast.StarExpr)
+
+	return recv, true
+}
+
+// getTypeParams returns the type parameters of a generic function or type.
+func getTypeParams(fieldList *ast.FieldList) (params []EntryTypeParam) {
+	if fieldList == nil {
+		return nil
+	}
+	for _, field := range fieldList.List {
+		constraint := types.ExprString(field.Type)
+		for _, name := range field.Names {
+			params = append(params, EntryTypeParam{Name: name.Name, Constraint: constraint})
+		}
+	}
+	return params
+}
+
+// receiverTypeParams returns the type parameters of the receiver of a method
+// of a generic type, as named by the receiver, such as K and V for *Foo[K, V],
+// along with the constraints declared by the type.
+func receiverTypeParams(funcDecl *ast.FuncDecl, typeParams []EntryTypeParam) (params []EntryTypeParam) {
+
+	expr := funcDecl.Recv.List[0].Type
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+			continue
+		case *ast.StarExpr:
+			expr = e.X
+			continue
+		}
+		break
+	}
+
+	var indices []ast.Expr
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		indices = e.Indices
+	}
+
+	for i, index := range indices {
+		param := EntryTypeParam{Name: types.ExprString(index)}
+		if i < len(typeParams) {
+			param.Constraint = typeParams[i].Constraint
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// receiverTypeName returns the name of the type the method is declared on,
+// such as Foo for receivers of type Foo, *Foo or *Foo[K, V].
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+
+	return baseTypeName(funcDecl.Recv.List[0].Type)
+}
+
+// baseTypeName returns the name of the type named by the expression, without
+// pointer, type arguments or package, such as Foo for *pkg.Foo[time.Time].
+func baseTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel.Name
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
 func hasSpecificResultType(entry Entry, result string) bool {
 	for _, res := range entry.Func.Results {
-		if res.Type == result {
+		if res.Type == result || res.QualifiedType == result {
 			return true
 		}
 	}
@@ -231,21 +688,23 @@ func getFuncInfos(file *ast.File) (entries []Entry) {
 	for _, decl := range file.Decls {
 		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
 
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				if starExpr, ok := funcDecl.Recv.List[0].Type.(*ast.StarExpr); ok {
-					if ident, ok := starExpr.X.(*ast.Ident); ok && ident.Name != "" {
-						continue
-					}
-				}
+			if _, ok := getFuncReceiver(funcDecl); ok {
+				continue
 			}
 
 			funcInfo := Entry{
+				Kind: FuncKind,
 				Func: EntryFunc{
 					Name:       funcDecl.Name.Name,
+					TypeParams: getTypeParams(funcDecl.Type.TypeParams),
 					Parameters: getFuncParams(funcDecl.Type.Params),
 					Results:    getFuncParams(funcDecl.Type.Results),
+					Variadic:   isVariadic(funcDecl.Type.Params),
+					Named:      hasNamedResults(funcDecl.Type.Results),
 				},
 				Comments: getComments(funcDecl.Doc),
+				node:     funcDecl,
+				docs:     []*ast.CommentGroup{funcDecl.Doc},
 			}
 			entries = append(entries, funcInfo)
 		}
@@ -254,6 +713,20 @@ func getFuncInfos(file *ast.File) (entries []Entry) {
 	return entries
 }
 
+// isVariadic reports whether the last parameter of the list is variadic.
+func isVariadic(fieldList *ast.FieldList) bool {
+	if fieldList == nil || len(fieldList.List) == 0 {
+		return false
+	}
+	_, ok := fieldList.List[len(fieldList.List)-1].Type.(*ast.Ellipsis)
+	return ok
+}
+
+// hasNamedResults reports whether the results of the list are named.
+func hasNamedResults(fieldList *ast.FieldList) bool {
+	return fieldList != nil && len(fieldList.List) > 0 && len(fieldList.List[0].Names) > 0
+}
+
 func getFuncParams(fieldList *ast.FieldList) []EntryFuncType {
 	var params []EntryFuncType
 
@@ -266,10 +739,11 @@ func getFuncParams(fieldList *ast.FieldList) []EntryFuncType {
 					params = append(params, EntryFuncType{
 						Name: name.Name,
 						Type: paramType,
+						expr: field.Type,
 					})
 				}
 			} else {
-				params = append(params, EntryFuncType{Type: paramType})
+				params = append(params, EntryFuncType{Type: paramType, expr: field.Type})
 			}
 		}
 	}
@@ -291,44 +765,251 @@ func getComments(group *ast.CommentGroup) []string {
 
 func (c *Collector) filterFiles(p *packages.Package) (entries []Entry, err error) {
 
+	ifaces := newInterfaceIndex(p)
+
+	var pkgEntry Entry
+
+	var modName string
+	if p.Module != nil {
+		modName = p.Module.Path
+	}
+
 	for _, file := range p.Syntax {
 
-		var modName string
-		if p.Module != nil {
-			modName = p.Module.Path
+		filename, relFile := filePaths(p, file)
+
+		docSrc := commentSource{pkg: p, file: file, positions: commentPositions(p.Fset, file.Doc)}
+		if ans, ok := c.docAnnotations([]*ast.CommentGroup{file.Doc}, docSrc); ok {
+			if pkgEntry.Annotations == nil {
+				pkgEntry = Entry{
+					Kind:     PackageKind,
+					Module:   modName,
+					File:     filename,
+					RelFile:  relFile,
+					Path:     p.PkgPath,
+					Package:  p.Name,
+					Position: p.Fset.Position(file.Package),
+					End:      p.Fset.Position(file.Name.End()),
+					node:     file,
+					src:      docSrc,
+				}
+			}
+			pkgEntry.Comments = append(pkgEntry.Comments, getComments(file.Doc)...)
+			pkgEntry.docs = append(pkgEntry.docs, file.Doc)
+			pkgEntry.Annotations = append(pkgEntry.Annotations, ans...)
 		}
 
 		var cap []Entry
-		cap = append(cap, getStructInfos(file)...)
+		cap = append(cap, getStructInfos(file, p.Syntax, ifaces)...)
 		cap = append(cap, getFuncInfos(file)...)
+		cap = append(cap, getVarInfos(file)...)
 
 		for _, ens := range cap {
 
-			ens.File = file.Name.String()
+			// methods may be declared in another file than their type, or than the
+			// interface embedding them, even in another package
+			declFile, declPkg := file, p
+			if ens.src.file != nil {
+				declFile = ens.src.file
+			}
+			if ens.src.pkg != nil {
+				declPkg = ens.src.pkg
+			}
+
+			ens.File, ens.RelFile = filePaths(declPkg, declFile)
+			ens.Position = declPkg.Fset.Position(ens.node.Pos())
+			ens.End = declPkg.Fset.Position(ens.node.End())
 			ens.Header = c.parseHeader(ens.Comments)
 			ens.Path = p.PkgPath
 			ens.Module = modName
 			ens.Package = p.Name
 
-			if ans, ok := c.getAnnotations(ens.Comments); ok {
-				ens.Annotations = ans
+			if ens.Var.Name != "" {
+				ens.Var = typedVar(p, ens.Var)
+			}
+			ens.Func.Parameters = typedFuncTypes(declPkg, ens.Func.Parameters)
+			ens.Func.Results = typedFuncTypes(declPkg, ens.Func.Results)
+
+			ens.src = commentSource{pkg: declPkg, file: declFile, positions: commentPositions(declPkg.Fset, ens.docs...)}
+			ans, ok := c.docAnnotations(ens.docs, ens.src)
+			ens.Annotations = ans
+
+			var fieldEntries []Entry
+			for i, field := range ens.Fields {
+				src := commentSource{pkg: p, file: file, positions: commentPositions(p.Fset, field.field.Doc, field.field.Comment)}
+				if ans, ok := c.docAnnotations([]*ast.CommentGroup{field.field.Doc, field.field.Comment}, src); ok {
+					ens.Fields[i].Annotations = ans
+					fieldEntries = append(fieldEntries, c.fieldEntry(ens, ens.Fields[i], src))
+				}
+			}
+
+			if ok || len(fieldEntries) > 0 {
 				entries = append(entries, ens)
+				entries = append(entries, fieldEntries...)
 			}
 
 		}
 
 	}
 
+	if pkgEntry.Annotations != nil {
+		pkgEntry.Header = c.parseHeader(pkgEntry.Comments)
+		entries = append([]Entry{pkgEntry}, entries...)
+	}
+
 	return entries, err
 }
 
-func (c *Collector) getAnnotations(cmts []string) (ans []Annotation, ok bool) {
+// filePaths returns the absolute path of the file and its path relative to
+// the module root.
+func filePaths(p *packages.Package, file *ast.File) (string, string) {
+	filename := p.Fset.File(file.Pos()).Name()
+	if p.Module == nil {
+		return filename, filepath.ToSlash(filename)
+	}
+	relFile, err := filepath.Rel(p.Module.Dir, filename)
+	if err != nil {
+		relFile = filename
+	}
+	return filename, filepath.ToSlash(relFile)
+}
+
+// typedVar completes the constant or variable with its type and, for
+// constants, its value as checked by go/types.
+func typedVar(p *packages.Package, v EntryVar) EntryVar {
+	if p.Types == nil {
+		return v
+	}
+	obj := p.Types.Scope().Lookup(v.Name)
+	if obj == nil {
+		return v
+	}
+	v.Type = types.TypeString(obj.Type(), types.RelativeTo(p.Types))
+	if c, ok := obj.(*types.Const); ok {
+		v.Value = constantValue(c)
+	}
+	return v
+}
+
+// typedFuncTypes completes the parameters or results with their types as
+// checked by go/types.
+func typedFuncTypes(p *packages.Package, params []EntryFuncType) []EntryFuncType {
+	if p.TypesInfo == nil {
+		return params
+	}
+	for i, param := range params {
+		t := typeOf(p.TypesInfo, param.expr)
+		if t == nil {
+			continue
+		}
+		params[i].GoType = t
+		params[i].QualifiedType = types.TypeString(t, nil)
+		switch u := t.(type) {
+		case *types.TypeParam:
+			params[i].TypeParam = true
+			params[i].Constraint = types.TypeString(u.Constraint(), nil)
+		case *types.Pointer:
+			params[i].Pointer = true
+		case *types.Slice, *types.Array:
+			params[i].Slice = true
+		case *types.Map:
+			params[i].Map = true
+		}
+		if named := namedType(t); named != nil && named.Obj().Pkg() != nil {
+			params[i].Package = named.Obj().Pkg().Path()
+		}
+	}
+	return params
+}
+
+// typeOf returns the type of the type expression, which is a slice for the
+// ...T of a variadic parameter.
+func typeOf(info *types.Info, expr ast.Expr) types.Type {
+	if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+		if elt := info.TypeOf(ellipsis.Elt); elt != nil {
+			return types.NewSlice(elt)
+		}
+		return nil
+	}
+	return info.TypeOf(expr)
+}
+
+// namedType returns the named type behind pointers, slices, arrays and the
+// values of maps, if any.
+func namedType(t types.Type) *types.Named {
+	for {
+		switch u := t.(type) {
+		case *types.Named:
+			return u
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		default:
+			return nil
+		}
+	}
+}
+
+// fieldEntry returns the entry of an annotated field of the struct entry.
+func (c *Collector) fieldEntry(structEntry Entry, field EntryField, src commentSource) Entry {
+	fset := src.pkg.Fset
+	return Entry{
+		Kind:        FieldKind,
+		Header:      c.parseHeader(field.Comments),
+		Comments:    field.Comments,
+		Module:      structEntry.Module,
+		File:        structEntry.File,
+		RelFile:     structEntry.RelFile,
+		Path:        structEntry.Path,
+		Package:     structEntry.Package,
+		Position:    fset.Position(field.field.Pos()),
+		End:         fset.Position(field.field.End()),
+		Struct:      structEntry.Struct,
+		Field:       field,
+		Annotations: field.Annotations,
+		node:        field.field,
+		docs:        []*ast.CommentGroup{field.field.Doc, field.field.Comment},
+		src:         src,
+	}
+}
+
+// docAnnotations extracts the annotations of the comment groups documenting
+// an entry. Each group is parsed on its own, so that an annotation can't
+// continue into the next group, and only once, however many entries it
+// documents, as the doc of a const block does.
+func (c *Collector) docAnnotations(groups []*ast.CommentGroup, src commentSource) (ans []Annotation, ok bool) {
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		groupAns, parsed := c.parsed[group]
+		if !parsed {
+			groupSrc := src
+			groupSrc.positions = commentPositions(src.pkg.Fset, group)
+			groupAns, _ = c.getAnnotations(getComments(group), groupSrc)
+			c.parsed[group] = groupAns
+		}
+		ans = append(ans, groupAns...)
+	}
+
+	return ans, len(ans) > 0
+}
+
+// getAnnotations extracts the annotations of the comments. The source is
+// used to resolve references to constants and to report diagnostics.
+func (c *Collector) getAnnotations(cmts []string, src commentSource) (ans []Annotation, ok bool) {
 
 	log.Tracef("get comments comments")
 
 	var contains bool
-	for _, cmt := range cmts {
-		an, ok := c.extractAnnotation(cmt)
+	for _, line := range c.syntax.join(c.syntax.lines(cmts)) {
+		an, ok := c.extractAnnotation(line, cmts, src)
 		if !ok {
 			continue
 		}
@@ -342,88 +1023,62 @@ func (c *Collector) getAnnotations(cmts []string) (ans []Annotation, ok bool) {
 	return ans, true
 }
 
-func (c *Collector) extractAnnotation(cmt string) (Annotation, bool) {
+func (c *Collector) extractAnnotation(line annotationLine, cmts []string, src commentSource) (Annotation, bool) {
 
-	log.Tracef("extracting an annotation from the comment. %s", cmt)
+	log.Tracef("extracting an annotation from the comment. %s", line.text)
 
-	if !strings.HasPrefix(cmt, "// @") {
-		log.Debugf("the comment is not an annotation. %s", cmt)
+	if !c.syntax.isAnnotation(line.lines[0]) {
+		log.Debugf("the comment is not an annotation. %s", line.text)
 		return Annotation{}, false
 	}
 
-	if !c.isValidAnnotation(cmt) {
-		log.Warnf("The annotation does not follow the format and will be ignored. %s", cmt)
+	// the name starts the text, so the filters apply even to malformed
+	// annotations, which are then ignored without a diagnostic
+	text, offset, _ := c.syntax.trim(line.lines[0])
+	if !c.isAllowedAnnotation(text) {
+		log.Warnf("The annotation will be ignored as it is not included in the filters. %s", line.text)
 		return Annotation{}, false
 	}
 
-	var allowed bool
-	for _, filter := range c.filters {
-		a := strings.Join([]string{"@", filter}, "")
-		if strings.Contains(cmt, a) {
-			allowed = true
-			break
+	an, err := c.syntax.parse(line, src.resolveConst)
+	if err != nil {
+		at := func(cl commentLine, col int) token.Position {
+			return positionOf(cmts, src.positions, cl, col)
 		}
-	}
-
-	if len(c.filters) == 0 {
-		allowed = true
-	}
-
-	if !allowed {
-		log.Warnf("The annotation is valid but will be ignored as it is not included in the filters. %s", cmt)
+		perr := err.(*ParseError)
+		pos := at(line.lines[0], perr.Column-1)
+		for _, cl := range line.lines {
+			if cl.row+1 == perr.Line {
+				pos = at(cl, perr.Column-1)
+			}
+		}
+		c.diagnostics = append(c.diagnostics, Diagnostic{
+			Position: pos,
+			Message:  fmt.Sprintf("malformed annotation %q: %s", perr.Text, perr.Reason),
+		})
+		log.Warnf("The annotation does not follow the format and will be ignored. %s: %s", pos, perr.Reason)
 		return Annotation{}, false
 	}
 
-	name, value := c.splitNameValue(cmt)
-	log.Infof("discovered annotation @%s with values (%s)", name, value)
-	return NewAnnotation(name, value), true
-}
+	an.Position = positionOf(cmts, src.positions, line.lines[0], line.lines[0].col+offset-len(c.syntax.sigil()))
 
-func (c *Collector) splitNameValue(cmt string) (name string, value string) {
-	fields := strings.Split(cmt, "(")
-	names := strings.Split(fields[0], "@")
-	if len(fields) > 1 {
-		value = strings.ReplaceAll(strings.TrimSpace(fields[1]), ")", "")
-	}
-	return strings.TrimSpace(names[1]), value
+	log.Infof("discovered annotation %s%s with values (%s)", c.syntax.sigil(), an.Name, an.Value)
+	return an, true
 }
 
-func (c *Collector) isValidAnnotation(input string) bool {
-
-	log.Tracef("checking if it is a valid annotation. %s", input)
-
-	split := strings.SplitN(input, "@", 2)
-
-	// Checking if string starts with "// @"
-	if !(len(split) == 2 && split[0] == "// ") {
-		return false
-	}
+// isAllowedAnnotation reports whether the annotation, whose text following the
+// sigil starts with its name, is included in the filters.
+func (c *Collector) isAllowedAnnotation(text string) bool {
 
-	// Checking if there are parentheses and if they have valid key-value pairs
-	parenSplit := strings.SplitN(split[1], "(", 2)
-	if len(parenSplit) == 1 {
-		spcSplit := strings.Split(parenSplit[0], " ")
-		if len(spcSplit) > 1 {
-			return false
-		}
-		log.Debugf("there is a valid annotation in the comment")
+	if len(c.filters) == 0 {
 		return true
 	}
-	if len(parenSplit) == 2 {
-		if !strings.Contains(parenSplit[1], ")") {
-			return false
-		}
-		parenContent := strings.Trim(parenSplit[1], " )")
-		pairs := strings.Split(parenContent, ",")
-		for _, pair := range pairs {
-			if !strings.Contains(pair, "=") {
-				return false
-			}
+
+	for _, filter := range c.filters {
+		if strings.HasPrefix(text, filter) {
+			return true
 		}
-		log.Debugf("there is a valid annotation in the comment")
-		return true
 	}
-
 	return false
 }
 
@@ -441,6 +1096,9 @@ func (c *Collector) isAllowedPackage(pkgPath string) bool {
 	return false
 }
 
+// parseHeader builds the header of an entry from its doc comments, read with
+// the semantics of go/doc/comment. The annotations are left out of the
+// summary and the description.
 func (c *Collector) parseHeader(cmts []string) EntryHeader {
 
 	log.Tracef("parsing header on the comment group")
@@ -449,14 +1107,93 @@ func (c *Collector) parseHeader(cmts []string) EntryHeader {
 		return EntryHeader{}
 	}
 
-	w := strings.Split(strings.ReplaceAll(cmts[0], "//", ""), " ")
-	var title string
-	if len(w) > 2 {
-		title = strings.Join(w[2:], " ")
+	raw := new(ast.CommentGroup)
+	for _, cmt := range cmts {
+		raw.List = append(raw.List, &ast.Comment{Text: cmt})
+	}
+
+	text := c.docText(cmts)
+	var pkg doc.Package
+	d := pkg.Parser().Parse(text)
+	printer := pkg.Printer()
+	printer.TextWidth = -1
+
+	var deprecated []string
+	for _, block := range d.Content {
+		if _, ok := block.(*comment.Paragraph); !ok {
+			continue
+		}
+		paragraph := string(printer.Text(&comment.Doc{Content: []comment.Block{block}}))
+		if strings.HasPrefix(paragraph, "Deprecated: ") {
+			deprecated = append(deprecated, strings.TrimSpace(strings.TrimPrefix(paragraph, "Deprecated: ")))
+		}
 	}
 
 	return EntryHeader{
-		Title:       title,
-		Description: "// TODO",
+		Title:       pkg.Synopsis(text),
+		Description: strings.TrimSuffix(string(printer.Text(d)), "\n"),
+		Deprecated:  strings.Join(deprecated, "\n\n"),
+		Doc:         raw.Text(),
+	}
+}
+
+// docText returns the text of the comments without their comment markers,
+// their directives nor their annotations, including the lines their arguments
+// continue on.
+func (c *Collector) docText(cmts []string) string {
+
+	skip := make(map[int]bool)
+	for _, l := range c.syntax.join(c.syntax.lines(cmts)) {
+		if c.syntax.isAnnotation(l.lines[0]) {
+			for _, cl := range l.lines {
+				skip[cl.row] = true
+			}
+		}
+	}
+
+	var lines []string
+	var row int
+	for _, cmt := range cmts {
+		var text []string
+		if strings.HasPrefix(cmt, "//") {
+			if isDirective(cmt[2:]) {
+				row++
+				continue
+			}
+			text = []string{strings.TrimPrefix(strings.TrimPrefix(cmt, "//"), " ")}
+		} else {
+			text = strings.Split(strings.TrimSuffix(strings.TrimPrefix(cmt, "/*"), "*/"), "\n")
+		}
+		for _, line := range text {
+			if !skip[row] {
+				lines = append(lines, strings.TrimRight(line, " \t"))
+			}
+			row++
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isDirective reports whether the text of a // comment, without its marker,
+// is a directive such as go:generate or nolint:gocyclo, which
+// ast.CommentGroup.Text leaves out as well.
+func isDirective(text string) bool {
+	if strings.HasPrefix(text, "line ") || strings.HasPrefix(text, "extern ") || strings.HasPrefix(text, "export ") {
+		return true
 	}
+
+	colon := strings.Index(text, ":")
+	if colon <= 0 || colon+1 >= len(text) {
+		return false
+	}
+	for i := 0; i <= colon+1; i++ {
+		if i == colon {
+			continue
+		}
+		if b := text[i]; !('a' <= b && b <= 'z' || '0' <= b && b <= '9') {
+			return false
+		}
+	}
+	return true
 }
@@ -1,46 +1,630 @@
 package annotation
 
 import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
-func TestCollector_isValidAnnotation(t *testing.T) {
+func TestCollector_getAnnotations(t *testing.T) {
 	tests := []struct {
 		name     string
-		input    string
-		expected bool
+		input    []string
+		expected []string
 	}{
-		{"Test1", "// @RestResponse(code=201, type=github.com/americanas-go/inject/examples/simple.Response, description=tiam sed efficitur purus at lacinia magna)", true},
-		{"Test2", "// @RestRequest(code=201)", true},
-		{"Test3", "// @RestAction (type=action, data=123)", true},
-		{"Test4", "// @RestAction (type=action, data=123)", true},
-		{"Test5", "// @RestAction ( type=action, data=123)", true},
-		{"Test6", "// @RestAction ( type=action, data=123 )", true},
-		{"Test7", "// @RestAction (type=action,data=123, xpto=456)", true},
-		{"Test8", "// @RestRequest(code=201)", true},
-		{"Test9", "//@RestAction (type=action, data=123)", false},
-		{"Test10", "//@RestAction (type=action, data=123)", false},
-		{"Test11", "//@RestAction ( type=action, data=123)", false},
-		{"Test12", "//@RestAction ( type=action, data=123 )", false},
-		{"Test13", "//@RestAction (type=action,data=123, xpto=456)", false},
-		{"Test14", "// FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit", false},
-		{"Test15", "//FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit", false},
-		{"Test16", "// @MyAnnotation(code=201)", true},
-		{"Test16", "// @MyAnnotation(code=201", false},
-		{"Test16", "// @A Param query foo bool true tiam sed efficitur purus", false},
-		{"Test16", "// @Invoke", true},
+		{"Test1", []string{"// FooFunc Lorem ipsum", "// @RestRouter(path=/, method=POST)"}, []string{"RestRouter"}},
+		{"Test2", []string{"// @RestQueryParam(name=foo,", "//   type=bool,", "//   required=true)"}, []string{"RestQueryParam"}},
+		{"Test3", []string{"// @RestQueryParam(", "//   name=foo, description=\"tiam sed (efficitur)", "//   purus\")", "// @Invoke"}, []string{"RestQueryParam", "Invoke"}},
+		{"Test4", []string{"// @MyAnnotation(code=201", "// @Invoke"}, []string{"Invoke"}},
+		{"Test5", []string{"// @RestResponse(description=don't)", "// Lorem ipsum"}, []string{"RestResponse"}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			c := &Collector{
-				filters: []string{""},
+			c := &Collector{}
+
+			ans, _ := c.getAnnotations(tt.input, commentSource{})
+
+			var got []string
+			for _, an := range ans {
+				got = append(got, an.Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("getAnnotations() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollector_parseHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected EntryHeader
+	}{
+		{"Test1", nil, EntryHeader{}},
+		{
+			"Test2",
+			[]string{"// FooFunc Lorem ipsum. Dolor sit amet.", "// @RestRouter(path=/, method=POST)"},
+			EntryHeader{
+				Title:       "FooFunc Lorem ipsum.",
+				Description: "FooFunc Lorem ipsum. Dolor sit amet.",
+				Doc:         "FooFunc Lorem ipsum. Dolor sit amet.\n@RestRouter(path=/, method=POST)\n",
+			},
+		},
+		{
+			"Test3",
+			[]string{"// Find finds a user", "// by its id.", "// @RestQueryParam(name=foo,", "//   required=true)", "//", "// It returns nil when missing.", "//", "// Deprecated: use Get."},
+			EntryHeader{
+				Title:       "Find finds a user by its id.",
+				Description: "Find finds a user by its id.\n\nIt returns nil when missing.\n\nDeprecated: use Get.",
+				Deprecated:  "use Get.",
+				Doc:         "Find finds a user\nby its id.\n@RestQueryParam(name=foo,\n  required=true)\n\nIt returns nil when missing.\n\nDeprecated: use Get.\n",
+			},
+		},
+		{
+			"Test4",
+			[]string{"// @Invoke"},
+			EntryHeader{Doc: "@Invoke\n"},
+		},
+		{
+			"Test5",
+			[]string{"//go:generate mockgen -source=x.go", "// Foo does x.", "// @Invoke", "//nolint:gocyclo"},
+			EntryHeader{
+				Title:       "Foo does x.",
+				Description: "Foo does x.",
+				Doc:         "Foo does x.\n@Invoke\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			c := &Collector{}
+
+			if got := c.parseHeader(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseHeader() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollector_getAnnotations_syntax(t *testing.T) {
+	tests := []struct {
+		name     string
+		syntax   Syntax
+		input    []string
+		expected []Annotation
+	}{
+		{"Test1", Syntax{}, []string{"//@Invoke", "/* @Inject */"}, nil},
+		{"Test2", Syntax{OptionalSpace: true}, []string{"//@Invoke", "//   @Inject(FooService)"}, []Annotation{
+			NewAnnotation("Invoke", ""),
+			NewAnnotation("Inject", "FooService"),
+		}},
+		{"Test3", Syntax{BlockComments: true}, []string{"/* @Invoke */", "/*\n * @Inject(FooService)\n * @Path(\n *   \"/users\")\n */"}, []Annotation{
+			NewAnnotation("Invoke", ""),
+			NewAnnotation("Inject", "FooService"),
+			NewAnnotation("Path", `"/users"`),
+		}},
+		{"Test4", Syntax{Sigil: "#"}, []string{"// #Invoke", "// @Inject"}, []Annotation{
+			NewAnnotation("Invoke", ""),
+		}},
+		{"Test5", MarkerSyntax(), []string{
+			"// +kubebuilder:object:root=true",
+			"// +kubebuilder:validation:Enum=a;b;c",
+			`// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"`,
+			"//+genclient",
+			"// @Invoke",
+		}, []Annotation{
+			newAnnotation("kubebuilder:object:root", "true", []Arg{{Value: true}}),
+			newAnnotation("kubebuilder:validation:Enum", "a;b;c", []Arg{{Value: "a;b;c"}}),
+			newAnnotation("kubebuilder:printcolumn", `name="Age",type=date,JSONPath=".metadata.creationTimestamp"`, []Arg{
+				{Name: "name", Value: "Age"},
+				{Name: "type", Value: "date"},
+				{Name: "JSONPath", Value: ".metadata.creationTimestamp"},
+			}),
+			newAnnotation("genclient", "", nil),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			c := &Collector{syntax: tt.syntax}
+
+			got, _ := c.getAnnotations(tt.input, commentSource{})
+			for i := range got {
+				got[i].Position = token.Position{}
 			}
 
-			if got := c.isValidAnnotation(tt.input); got != tt.expected {
-				t.Errorf("isValidAnnotation() = %v, want %v", got, tt.expected)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("getAnnotations() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
+
+// testdataPath returns the absolute path of the testdata package dir.
+func testdataPath(t *testing.T, dir string) string {
+	t.Helper()
+	path, err := filepath.Abs(filepath.Join("testdata", dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// collect collects the annotations of the testdata package dir with the
+// options, failing the test when Collect fails.
+func collect(t *testing.T, dir string, opts ...Option) *Collector {
+	t.Helper()
+	c, err := Collect(append([]Option{WithPath(testdataPath(t, dir))}, opts...)...)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	return c
+}
+
+func TestCollect_strict(t *testing.T) {
+
+	c := collect(t, "strict")
+	if len(c.Entries()) != 1 || len(c.Diagnostics()) != 2 {
+		t.Errorf("Collect() = %v entries and %v diagnostics, want 1 and 2", len(c.Entries()), len(c.Diagnostics()))
+	}
+
+	path := testdataPath(t, "strict")
+	_, err := Collect(WithPath(path), WithStrict())
+	diags, ok := err.(Diagnostics)
+	if !ok || len(diags) != 2 {
+		t.Fatalf("Collect() error = %v, want two diagnostics", err)
+	}
+
+	expected := []token.Position{
+		{Filename: filepath.Join(path, "strict.go"), Offset: 99, Line: 4, Column: 17},
+		{Filename: filepath.Join(path, "strict.go"), Offset: 255, Line: 12, Column: 16},
+	}
+	for i, diag := range diags {
+		if diag.Position != expected[i] {
+			t.Errorf("Collect() diagnostics[%d] = %v, want %v", i, diag.Position, expected[i])
+		}
+	}
+
+	c = collect(t, "strict", WithStrict(), WithFilters("Invoke"))
+	if len(c.Entries()) != 1 || len(c.Diagnostics()) != 0 {
+		t.Errorf("Collect() = %v entries and %v diagnostics, want 1 and 0", len(c.Entries()), len(c.Diagnostics()))
+	}
+}
+
+func TestCollect_constants(t *testing.T) {
+
+	c := collect(t, "consts")
+	if len(c.Entries()) != 1 {
+		t.Fatalf("Collect() = %v entries, want 1", len(c.Entries()))
+	}
+
+	ans := c.Entries()[0].Annotations
+	expected := []map[string]interface{}{
+		{"code": 201, "method": "POST", "timeout": 5 * time.Second},
+		{"name": "orders", "ratio": 0.5, "enabled": true, "raw": "defaultName", "unknown": "missing", "path": 200},
+	}
+	for i, an := range ans {
+		if !reflect.DeepEqual(an.Map, expected[i]) {
+			t.Errorf("Collect() annotations[%d] = %v, want %v", i, an.Map, expected[i])
+		}
+	}
+}
+
+func TestCollect_typeRefs(t *testing.T) {
+
+	c := collect(t, "typeref",
+		WithTypeRefs("RestResponse", "type"),
+		WithTypeRefs("RestRequest", "types"),
+		WithTypeRefs("Response", "type"),
+		WithTypeRefs("Enum", "type"),
+	)
+
+	entries := c.EntriesWith("RestRequest")
+	if len(entries) != 1 {
+		t.Fatalf("Collect() = %v entries, want 1", len(entries))
+	}
+
+	var got []string
+	for _, an := range entries[0].Annotations {
+		switch v := an.Map["type"].(type) {
+		case TypeRef:
+			got = append(got, v.String())
+		case nil:
+			for _, ref := range an.Map["types"].([]interface{}) {
+				got = append(got, ref.(TypeRef).String())
+			}
+		}
+	}
+
+	expected := []string{
+		"github.com/americanas-go/annotation/testdata/typeref/model.Response",
+		"*github.com/americanas-go/annotation/testdata/typeref/model.Error",
+		"github.com/americanas-go/annotation/testdata/typeref.Request",
+		"[]github.com/americanas-go/annotation/testdata/typeref/model.Error",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Collect() type references = %v, want %v", got, expected)
+	}
+
+	ref := entries[0].Annotations[0].Map["type"].(TypeRef)
+	if entry, ok := c.EntryOf(ref); !ok || entry.Struct != "Response" {
+		t.Errorf("EntryOf() = %v, %v", entry, ok)
+	}
+
+	var rp struct {
+		Type string `attr:"type"`
+	}
+	if err := entries[0].Annotations[0].Decode(&rp); err != nil || rp.Type != expected[0] {
+		t.Errorf("Decode() = %v, %v", rp, err)
+	}
+
+	endpoint := c.EntriesWith("RestEndpoint")[0].Annotations[0]
+	responses := endpoint.Map["responses"].([]interface{})
+	if ref, ok := responses[0].(Annotation).Map["type"].(TypeRef); !ok || ref.String() != expected[0] {
+		t.Errorf("Collect() nested type reference = %v", responses[0].(Annotation).Map["type"])
+	}
+
+	diags := c.Diagnostics()
+	if len(diags) != 3 || diags[0].Position.Line != 16 || diags[0].Position.Column != 4 {
+		t.Fatalf("Collect() diagnostics = %v, want three, the first at 16:4", diags)
+	}
+	if diags[1].Position.Line != 24 || !strings.Contains(diags[1].Message, "@Response(type)") {
+		t.Errorf("Collect() diagnostics[1] = %v", diags[1])
+	}
+	if diags[2].Position.Line != 28 || !strings.Contains(diags[2].Message, "@Enum(type)") {
+		t.Errorf("Collect() diagnostics[2] = %v, want one for the const block", diags[2])
+	}
+
+	_, err := Collect(WithPath(testdataPath(t, "typeref")), WithTypeRefs("Response", "type"), WithStrict())
+	if diags, ok := err.(Diagnostics); !ok || len(diags) != 1 || !strings.Contains(diags[0].Message, `unknown type "model.Nope"`) {
+		t.Errorf("Collect() error = %v, want the nested type reference", err)
+	}
+}
+
+func TestCollect_positions(t *testing.T) {
+
+	c := collect(t, "strict")
+	if len(c.Entries()) != 1 {
+		t.Fatalf("Collect() = %v entries, want 1", len(c.Entries()))
+	}
+
+	entry := c.Entries()[0]
+	filename := filepath.Join(testdataPath(t, "strict"), "strict.go")
+	if entry.File != filename || entry.RelFile != "testdata/strict/strict.go" {
+		t.Errorf("Collect() file = %v, %v", entry.File, entry.RelFile)
+	}
+
+	if entry.Position.Line != 6 || entry.Position.Column != 1 || entry.End.Line != 7 || entry.End.Column != 2 {
+		t.Errorf("Collect() position = %v - %v, want 6:1 - 7:2", entry.Position, entry.End)
+	}
+
+	expected := token.Position{Filename: filename, Offset: 112, Line: 5, Column: 4}
+	if an := entry.Annotations[0]; an.Name != "Invoke" || an.Position != expected {
+		t.Errorf("Collect() annotation position = %v, want %v", an.Position, expected)
+	}
+}
+
+func TestCollect_fields(t *testing.T) {
+
+	c := collect(t, "fields")
+
+	structs := c.EntriesWithField("Column")
+	if len(structs) != 1 || !structs[0].IsStruct() || structs[0].Struct != "User" {
+		t.Fatalf("EntriesWithField() = %v", structs)
+	}
+
+	var got []string
+	for _, field := range structs[0].Fields {
+		var names []string
+		for _, an := range field.Annotations {
+			names = append(names, an.Name)
+		}
+		got = append(got, fmt.Sprintf("%s %s %q %v %v", field.Name, field.Type, field.Tag, field.Embedded, names))
+	}
+	expected := []string{
+		`Base *Base "" true []`,
+		`Name string "json:\"name\"" false [Column Validate]`,
+		`CreatedAt time.Time "" false [Column]`,
+		`UpdatedAt time.Time "" false [Column]`,
+		`Age int "json:\"age\"" false [Validate]`,
+		`ignored bool "" false []`,
+		`Audit *Audit[time.Time] "" true []`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Collect() fields = %v, want %v", got, expected)
+	}
+
+	fields := c.EntriesWith("Validate")
+	if len(fields) != 2 || !fields[0].IsField() || fields[0].Field.Name != "Name" || fields[1].Field.Name != "Age" {
+		t.Fatalf("EntriesWith() = %v", fields)
+	}
+	if fields[1].Position.Line != 18 || fields[1].Annotations[0].Position.Line != 18 {
+		t.Errorf("EntriesWith() positions = %v, %v", fields[1].Position, fields[1].Annotations[0].Position)
+	}
+	if fields[0].Header.Title != "Name is the name of the user." || fields[1].Header.Title != "" {
+		t.Errorf("EntriesWith() headers = %v, %v", fields[0].Header, fields[1].Header)
+	}
+}
+
+func TestCollect_interfaces(t *testing.T) {
+
+	c := collect(t, "iface")
+
+	clients := c.EntriesWith("HttpClient")
+	if len(clients) != 1 || !clients[0].IsInterface() || clients[0].IsStruct() || clients[0].Interface != "UserClient" {
+		t.Fatalf("EntriesWith() = %v", clients)
+	}
+	if !reflect.DeepEqual(clients[0].Embeds, []string{"Finder", "Getter[User]", "io.Closer", "remote.Pinger"}) {
+		t.Errorf("EntriesWith() embeds = %v", clients[0].Embeds)
+	}
+
+	var got []string
+	for _, entry := range append(c.EntriesWith("Get"), c.EntriesWith("Post")...) {
+		if !entry.IsMethod() || entry.IsFunc() {
+			t.Errorf("EntriesWith() = %v, want a method", entry)
+		}
+		got = append(got, fmt.Sprintf("%s.%s %s %s %v", entry.Interface, entry.Func.Name, entry.Func.Signature(), filepath.Base(entry.File), entry.Annotations[0].Map["code"]))
+	}
+	expected := []string{
+		"Pinger.Health func Health() error remote.go <nil>",
+		"Finder.Find func Find(id int) (*User, error) finder.go 200",
+		"Getter.Value func Value() T getter.go <nil>",
+		"UserClient.Find func Find(id int) (*User, error) finder.go 200",
+		"UserClient.Value func Value() T getter.go <nil>",
+		"UserClient.Health func Health() error remote.go <nil>",
+		"Admin.Find func Find(id int) (*User, error) finder.go 200",
+		"UserClient.Create func Create(user User) error iface.go <nil>",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesWith() methods = %v, want %v", got, expected)
+	}
+
+	diags := c.Diagnostics()
+	if len(diags) != 1 || filepath.Base(diags[0].Position.Filename) != "finder.go" || diags[0].Position.Line != 9 {
+		t.Errorf("Collect() diagnostics = %v, want one at finder.go:9", diags)
+	}
+
+	if entry, ok := c.EntryOf(TypeRef{Package: clients[0].Path, Name: "UserClient"}); !ok || !entry.IsInterface() {
+		t.Errorf("EntryOf() = %v, %v", entry, ok)
+	}
+}
+
+func TestCollect_vars(t *testing.T) {
+
+	c := collect(t, "vars")
+
+	var got []string
+	for _, entry := range c.Entries() {
+		var names []string
+		for _, an := range entry.Annotations {
+			names = append(names, an.Name)
+		}
+		got = append(got, fmt.Sprintf("%v %v %s %s %v %v", entry.IsConst(), entry.IsVar(), entry.Var.Name, entry.Var.Type, entry.Var.Value, names))
+	}
+	expected := []string{
+		"true false Red Color 0 [Enum Value]",
+		"true false Green Color 1 [Enum Value]",
+		"true false Blue Color 2 [Enum]",
+		"true false Timeout time.Duration 5s [Config]",
+		"false true handler net/http.HandlerFunc <nil> [Route]",
+		"false true verbose bool <nil> [Flag]",
+		"false true debug bool <nil> [Flag]",
+		"false true retries int <nil> [Config]",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Collect() = %v, want %v", got, expected)
+	}
+
+	green := c.EntriesWith("Value")[1]
+	if green.Position.Line != 14 || green.Annotations[1].Position.Line != 14 || green.Annotations[1].Position.Column != 17 {
+		t.Errorf("EntriesWith() positions = %v, %v", green.Position, green.Annotations[1].Position)
+	}
+
+	_, err := Collect(WithPath(testdataPath(t, "vars")), WithStrict())
+	diags, ok := err.(Diagnostics)
+	if !ok || len(diags) != 1 {
+		t.Fatalf("Collect() error = %v, want one diagnostic", err)
+	}
+	if diags[0].Position.Line != 30 || diags[0].Message != `malformed annotation "@Enum(name=size": missing closing parenthesis` {
+		t.Errorf("Collect() diagnostics = %v", diags)
+	}
+}
+
+func TestCollect_packages(t *testing.T) {
+
+	c := collect(t, "pkgdoc")
+
+	pkgs := c.PackageEntries()
+	if len(pkgs) != 1 || !pkgs[0].IsPackage() || pkgs[0].IsStruct() || pkgs[0].Package != "pkgdoc" {
+		t.Fatalf("PackageEntries() = %v", pkgs)
+	}
+
+	var names []string
+	for _, an := range pkgs[0].Annotations {
+		names = append(names, an.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Module", "Layer"}) {
+		t.Errorf("PackageEntries() annotations = %v", names)
+	}
+	if pos := pkgs[0].Annotations[1].Position; filepath.Base(pos.Filename) != "layer.go" || pos.Line != 1 || pos.Column != 4 {
+		t.Errorf("PackageEntries() position = %v", pos)
+	}
+
+	entities := c.EntriesWith("Entity")
+	if len(entities) != 1 || entities[0].IsPackage() {
+		t.Fatalf("EntriesWith() = %v", entities)
+	}
+	if pkg, ok := c.PackageOf(entities[0]); !ok || pkg.Annotations[0].Map["name"] != "orders" {
+		t.Errorf("PackageOf() = %v, %v", pkg, ok)
+	}
+}
+
+func TestCollect_receivers(t *testing.T) {
+
+	c := collect(t, "receivers")
+
+	var got []string
+	for _, entry := range c.EntriesWith("Handler") {
+		recv := entry.Func.Receiver
+		got = append(got, fmt.Sprintf("%v %s.%s %q %s %v", entry.IsMethod(), entry.Struct, entry.Func.Name, recv.Name, recv.Type, recv.Pointer))
+	}
+	expected := []string{
+		`true Service.Value "s" Service false`,
+		`true Service.Pointer "s" *Service true`,
+		`true Service.Other "s" *Service true`,
+		`true Cache.Get "c" *Cache[K, V] true`,
+		`true Box.Unbox "" Box[T] false`,
+		`false .Free ""  false`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesWith() = %v, want %v", got, expected)
+	}
+
+	other := c.EntriesWith("Handler")[2]
+	if other.RelFile != "testdata/receivers/service_handlers.go" || other.Position.Line != 4 || filepath.Base(other.Annotations[0].Position.Filename) != "service_handlers.go" {
+		t.Errorf("EntriesWith() = %v, %v, %v", other.RelFile, other.Position, other.Annotations[0].Position)
+	}
+}
+
+func TestCollect_kinds(t *testing.T) {
+
+	c := collect(t, "kinds")
+
+	var got []string
+	for _, entry := range c.Entries() {
+		got = append(got, fmt.Sprintf("%s %s", entry.Kind, entry.Type))
+	}
+	expected := []string{
+		"package ",
+		"struct ",
+		"field ",
+		"interface ",
+		"method ",
+		"alias io.Closer",
+		"type string",
+		"func type func(ctx context.Context) error",
+		"method ",
+		"map map[string]string",
+		"slice []ID",
+		"array [32]byte",
+		"chan chan ID",
+		"pointer *User",
+		"func ",
+		"const ",
+		"var ",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Collect() kinds = %v, want %v", got, expected)
+	}
+
+	text, err := json.Marshal(map[string]Kind{"kind": c.Entries()[1].Kind})
+	if err != nil || string(text) != `{"kind":"struct"}` {
+		t.Errorf("Marshal() = %s, %v", text, err)
+	}
+	var kind Kind
+	if err := kind.UnmarshalText([]byte("func type")); err != nil || kind != FuncTypeKind {
+		t.Errorf("UnmarshalText() = %v, %v", kind, err)
+	}
+
+	if entries := c.EntriesOfKind(MapKind, SliceKind); len(entries) != 2 || entries[0].Struct != "Headers" || entries[1].Struct != "IDs" {
+		t.Errorf("EntriesOfKind() = %v", entries)
+	}
+	if entries := c.EntriesWithKind("Model", MethodKind); len(entries) != 2 || entries[0].Interface != "Reader" || entries[1].Struct != "Handler" {
+		t.Errorf("EntriesWithKind() = %v", entries)
+	}
+	if entries := c.EntriesWithKind("Column", StructKind); len(entries) != 0 {
+		t.Errorf("EntriesWithKind() = %v", entries)
+	}
+}
+
+func TestCollect_qualifiedTypes(t *testing.T) {
+
+	c := collect(t, "qualified", WithPackages("testdata/qualified"))
+
+	entries := c.EntriesWith("Handler")
+	if len(entries) != 1 {
+		t.Fatalf("EntriesWith() = %v", entries)
+	}
+
+	var got []string
+	for _, param := range append(entries[0].Func.Parameters, entries[0].Func.Results...) {
+		got = append(got, fmt.Sprintf("%s %s %q %v %v %v %v", param.Type, param.QualifiedType, param.Package, param.Pointer, param.Slice, param.Map, param.GoType != nil))
+	}
+	model := "github.com/americanas-go/annotation/testdata/typeref/model"
+	expected := []string{
+		`context.Context context.Context "context" false false false true`,
+		`*nethttp.Request *net/http.Request "net/http" true false false true`,
+		`[]model.Response []` + model + `.Response "` + model + `" false true false true`,
+		`map[string]*model.Response map[string]*` + model + `.Response "` + model + `" false false true true`,
+		`...string []string "" false true false true`,
+		`model.Error ` + model + `.Error "` + model + `" false false false true`,
+		`error error "" false false false true`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesWith() types = %v, want %v", got, expected)
+	}
+
+	fn := entries[0].Func
+	signature := "func Handle(ctx context.Context, req *nethttp.Request, users []model.Response, headers map[string]*model.Response, opts ...string) (model.Error, error)"
+	if !fn.Variadic || fn.Named || fn.Signature() != signature {
+		t.Errorf("EntriesWith() = %v %v %v", fn.Variadic, fn.Named, fn.Signature())
+	}
+
+	for _, arg := range []string{"*nethttp.Request", "*net/http.Request"} {
+		if entries := c.EntriesWithArgType("Handler", arg); len(entries) != 1 {
+			t.Errorf("EntriesWithArgType(%s) = %v", arg, entries)
+		}
+	}
+	if entries := c.EntriesWithResultType("Handler", model+".Error"); len(entries) != 1 {
+		t.Errorf("EntriesWithResultType() = %v", entries)
+	}
+}
+
+func TestCollect_generics(t *testing.T) {
+
+	c := collect(t, "generics")
+
+	repos := c.EntriesWith("Repository")
+	expected := []EntryTypeParam{{Name: "T", Constraint: "Entity"}, {Name: "K", Constraint: "comparable"}}
+	if len(repos) != 1 || !reflect.DeepEqual(repos[0].TypeParams, expected) {
+		t.Fatalf("EntriesWith() = %v", repos)
+	}
+
+	var got [][]EntryTypeParam
+	for _, entry := range c.EntriesWith("Handler") {
+		got = append(got, entry.Func.TypeParams)
+	}
+	want := [][]EntryTypeParam{
+		{{Name: "E", Constraint: "Entity"}, {Name: "K", Constraint: "comparable"}},
+		{{Name: "T", Constraint: "any"}, {Name: "U", Constraint: "~int | ~string"}},
+		{{Name: "T", Constraint: "Entity"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EntriesWith() type params = %v, want %v", got, want)
+	}
+
+	entity := "github.com/americanas-go/annotation/testdata/generics.Entity"
+	for _, arg := range []string{"Entity", entity} {
+		entries := c.EntriesWithArgType("Handler", arg)
+		if len(entries) != 2 || entries[0].Func.Name != "Save" || entries[1].Func.Name != "Store" {
+			t.Errorf("EntriesWithArgType(%s) = %v", arg, entries)
+		}
+	}
+
+	param := c.EntriesWith("Handler")[2].Func.Parameters[0]
+	if !param.TypeParam || param.Constraint != entity {
+		t.Errorf("EntriesWith() parameter = %+v", param)
+	}
+}
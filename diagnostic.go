@@ -0,0 +1,67 @@
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Diagnostic is a problem found in the annotations while collecting them.
+type Diagnostic struct {
+	Position token.Position // Where the problem was found
+	Message  string         // What the problem is
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s", d.Position, d.Message)
+}
+
+// Diagnostics is the list of problems found while collecting annotations.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	msgs := make([]string, 0, len(d))
+	for _, diag := range d {
+		msgs = append(msgs, diag.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// commentPositions returns the position of each comment of the groups.
+func commentPositions(fset *token.FileSet, groups ...*ast.CommentGroup) (positions []token.Position) {
+	if fset == nil {
+		return nil
+	}
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, cmt := range group.List {
+			positions = append(positions, fset.Position(cmt.Slash))
+		}
+	}
+	return positions
+}
+
+// positionOf returns the source position of the column col of a comment
+// line. Without comment positions, lines and columns are relative to the
+// first comment.
+func positionOf(cmts []string, positions []token.Position, cl commentLine, col int) token.Position {
+	if cl.cmt >= len(positions) {
+		return token.Position{Line: cl.row + 1, Column: col + 1}
+	}
+
+	base := positions[cl.cmt]
+	if cl.line == 0 {
+		base.Offset += col
+		base.Column += col
+		return base
+	}
+
+	lines := strings.SplitAfterN(cmts[cl.cmt], "\n", cl.line+1)
+	base.Offset += len(cmts[cl.cmt]) - len(lines[cl.line]) + col
+	base.Line += cl.line
+	base.Column = col + 1
+	return base
+}
@@ -1,45 +1,192 @@
 package annotation
 
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
 // EntryHeader represents the metadata for an entry.
 type EntryHeader struct {
-	Title       string // Title of the entry
-	Description string // Description of the entry
+	Title       string // Summary sentence of the doc comment
+	Description string // Text of the doc comment, without its annotations
+	Deprecated  string // Text of the "Deprecated:" paragraphs of the doc comment
+	Doc         string // Raw text of the doc comment
 }
 
 // EntryFuncType represents a parameter or a result type of a function.
 type EntryFuncType struct {
-	Name string // Name of the parameter/result
-	Type string // Type of the parameter/result
+	Name          string     // Name of the parameter/result
+	Type          string     // Type of the parameter/result, as written
+	QualifiedType string     // Type qualified by import paths, e.g. *net/http.Request
+	Package       string     // Import path of the named type, without pointers, slices and maps
+	Pointer       bool       // Whether the type is a pointer
+	Slice         bool       // Whether the type is a slice or an array
+	Map           bool       // Whether the type is a map
+	TypeParam     bool       // Whether the type is a type parameter
+	Constraint    string     // Constraint of the type parameter, qualified by import paths
+	GoType        types.Type `yaml:"-" json:"-"` // Type checked type
+
+	expr ast.Expr
+}
+
+// EntryTypeParam represents a type parameter of a generic function or type.
+type EntryTypeParam struct {
+	Name       string // Name of the type parameter
+	Constraint string // Constraint of the type parameter, e.g. any or ~int | ~string
+}
+
+// EntryReceiver represents the receiver of a method.
+type EntryReceiver struct {
+	Name    string // Name of the receiver, empty when unnamed
+	Type    string // Type of the receiver, e.g. *Foo[T]
+	Pointer bool   // Whether the receiver is a pointer
 }
 
 // EntryFunc represents a function and its details.
 type EntryFunc struct {
-	Name       string          // Name of the function
-	Parameters []EntryFuncType // Parameters of the function
-	Results    []EntryFuncType // Results of the function
+	Name       string           // Name of the function
+	Receiver   EntryReceiver    // Receiver of the method, zero for functions
+	TypeParams []EntryTypeParam // Type parameters of the function, or of the receiver of the method
+	Parameters []EntryFuncType  // Parameters of the function
+	Results    []EntryFuncType  // Results of the function
+	Variadic   bool             // Whether the last parameter is variadic, as in ...string
+	Named      bool             // Whether the results are named
+}
+
+// Signature returns the Go signature of the function, as in
+// func (s *Service) Find(ctx context.Context, id int) (*User, error). Types
+// are written as in the source file.
+func (f EntryFunc) Signature() string {
+	var b strings.Builder
+	b.WriteString("func ")
+	if f.Receiver.Type != "" {
+		b.WriteString("(")
+		if f.Receiver.Name != "" {
+			b.WriteString(f.Receiver.Name + " ")
+		}
+		b.WriteString(f.Receiver.Type + ") ")
+	}
+	b.WriteString(f.Name)
+	if f.Receiver.Type == "" && len(f.TypeParams) > 0 {
+		params := make([]string, 0, len(f.TypeParams))
+		for _, param := range f.TypeParams {
+			params = append(params, param.Name+" "+param.Constraint)
+		}
+		b.WriteString("[" + strings.Join(params, ", ") + "]")
+	}
+	b.WriteString("(" + joinFuncTypes(f.Parameters) + ")")
+	switch {
+	case len(f.Results) == 1 && !f.Named:
+		b.WriteString(" " + f.Results[0].Type)
+	case len(f.Results) > 0:
+		b.WriteString(" (" + joinFuncTypes(f.Results) + ")")
+	}
+	return b.String()
+}
+
+func joinFuncTypes(params []EntryFuncType) string {
+	list := make([]string, 0, len(params))
+	for _, param := range params {
+		if param.Name != "" {
+			list = append(list, param.Name+" "+param.Type)
+		} else {
+			list = append(list, param.Type)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// constraintOf returns the constraint, as written, of the type parameter
+// named name.
+func (f EntryFunc) constraintOf(name string) string {
+	for _, param := range f.TypeParams {
+		if param.Name == name {
+			return param.Constraint
+		}
+	}
+	return ""
+}
+
+// EntryVar represents a constant or a variable.
+type EntryVar struct {
+	Name  string      // Name of the constant/variable
+	Type  string      // Type of the constant/variable
+	Value interface{} // Value of the constant, nil for variables
+	Const bool        // Whether it is a constant
+}
+
+// EntryField represents a field of a struct.
+type EntryField struct {
+	Name        string       // Name of the field, or of its type when embedded
+	Type        string       // Type of the field
+	Tag         string       // Go struct tag of the field
+	Embedded    bool         // Whether the field is embedded
+	Comments    []string     // Doc and line comments of the field
+	Annotations []Annotation // Annotations for the field
+
+	field *ast.Field
 }
 
 // Entry represents a single entry parsed from the *ast.File.
 type Entry struct {
+	Kind        Kind        // Kind of declaration of the entry
 	Header      EntryHeader // Metadata for the entry
 	Comments    []string
-	Module      string       // Name of the module where the entry is located
-	File        string       // Name of the file where the entry is located
-	Path        string       // Path to the file where the entry is located
-	Package     string       // Name of the package where the entry is located
-	Func        EntryFunc    // Details about the function in the entry
-	Struct      string       // Name of the struct in the entry
-	Annotations []Annotation // Annotations for the entry
+	Module      string           // Name of the module where the entry is located
+	File        string           // Absolute path of the file where the entry is located
+	RelFile     string           // Path of the file relative to the module root
+	Path        string           // Path to the file where the entry is located
+	Package     string           // Name of the package where the entry is located
+	Position    token.Position   // Start of the declaration
+	End         token.Position   // End of the declaration
+	Func        EntryFunc        // Details about the function in the entry
+	Struct      string           // Name of the type in the entry, or of the receiver type of the method
+	Type        string           // Type the type in the entry is defined from, unless it is a struct
+	Fields      []EntryField     // Fields of the struct in the entry
+	Field       EntryField       // Details about the struct field in the entry
+	Interface   string           // Name of the interface in the entry
+	Embeds      []string         // Types embedded by the interface in the entry
+	TypeParams  []EntryTypeParam // Type parameters of the generic type in the entry
+	Var         EntryVar         // Details about the constant/variable in the entry
+	Annotations []Annotation     // Annotations for the entry
+
+	node ast.Node
+	docs []*ast.CommentGroup
+	src  commentSource
 }
 
 func (b *Entry) IsStruct() bool {
-	return b.Struct != "" && b.Func.Name == ""
+	return b.Kind == StructKind
+}
+
+func (b *Entry) IsInterface() bool {
+	return b.Kind == InterfaceKind
+}
+
+func (b *Entry) IsField() bool {
+	return b.Kind == FieldKind
+}
+
+func (b *Entry) IsConst() bool {
+	return b.Kind == ConstKind
+}
+
+func (b *Entry) IsVar() bool {
+	return b.Kind == VarKind
+}
+
+// IsPackage reports whether the entry is a package, annotated in its
+// package doc comments.
+func (b *Entry) IsPackage() bool {
+	return b.Kind == PackageKind
 }
 
 func (b *Entry) IsFunc() bool {
-	return b.Struct == "" && b.Func.Name != ""
+	return b.Kind == FuncKind
 }
 
 func (b *Entry) IsMethod() bool {
-	return b.Struct != "" && b.Func.Name != ""
+	return b.Kind == MethodKind
 }
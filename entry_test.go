@@ -0,0 +1,68 @@
+package annotation
+
+import (
+	"testing"
+)
+
+func TestEntryFunc_Signature(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   EntryFunc
+		want string
+	}{
+		{
+			name: "Test1",
+			fn:   EntryFunc{Name: "Run"},
+			want: "func Run()",
+		},
+		{
+			name: "Test2",
+			fn: EntryFunc{
+				Name:       "Find",
+				Receiver:   EntryReceiver{Name: "s", Type: "*Service", Pointer: true},
+				Parameters: []EntryFuncType{{Name: "ctx", Type: "context.Context"}, {Name: "id", Type: "int"}},
+				Results:    []EntryFuncType{{Type: "*User"}, {Type: "error"}},
+			},
+			want: "func (s *Service) Find(ctx context.Context, id int) (*User, error)",
+		},
+		{
+			name: "Test3",
+			fn: EntryFunc{
+				Name:       "Unbox",
+				Receiver:   EntryReceiver{Type: "Box[T]"},
+				TypeParams: []EntryTypeParam{{Name: "T", Constraint: "any"}},
+				Results:    []EntryFuncType{{Type: "T"}},
+			},
+			want: "func (Box[T]) Unbox() T",
+		},
+		{
+			name: "Test4",
+			fn: EntryFunc{
+				Name:       "Map",
+				TypeParams: []EntryTypeParam{{Name: "T", Constraint: "any"}, {Name: "U", Constraint: "~int | ~string"}},
+				Parameters: []EntryFuncType{{Name: "items", Type: "[]T"}, {Name: "opts", Type: "...string"}},
+				Results:    []EntryFuncType{{Name: "out", Type: "[]U"}},
+				Variadic:   true,
+				Named:      true,
+			},
+			want: "func Map[T any, U ~int | ~string](items []T, opts ...string) (out []U)",
+		},
+		{
+			name: "Test5",
+			fn: EntryFunc{
+				Name:       "Write",
+				Parameters: []EntryFuncType{{Type: "[]byte"}},
+				Results:    []EntryFuncType{{Name: "n", Type: "int"}, {Name: "err", Type: "error"}},
+				Named:      true,
+			},
+			want: "func Write([]byte) (n int, err error)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn.Signature(); got != tt.want {
+				t.Errorf("Signature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
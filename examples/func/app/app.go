@@ -7,8 +7,7 @@ import (
 // FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
 // @RestRouter(path=/, method=POST)
 // @RestRouter(path=/foo, method=GET)
-// @RestConsume(type=application/json)
-// @RestConsume(type=application/yaml)
+// @RestConsume(types=[application/json, application/yaml])
 // @RestProduce(type=application/json)
 // @RestQueryParam(name=foo, type=bool, required=true, description= tiam sed efficitur purus)
 // @RestQueryParam(name=bar, type=string, required=true, description= tiam sed efficitur purus)
@@ -18,6 +17,10 @@ import (
 // @RestHeader(name=bar, type=string, required=true, description=tiam sed efficitur purus)
 // @RestRequestBody(type=github.com/americanas-go/inject/examples/simple.Request)
 // @RestResponse(code=201, type=github.com/americanas-go/inject/examples/simple.Response, description=tiam sed efficitur purus at lacinia magna)
+// @RestResponse(
+// code=404,
+// type=github.com/americanas-go/inject/examples/simple.Error,
+// description="not found, tiam sed efficitur purus")
 // @IgnoredAnnotation(param=201)
 func FooFunc(ctx context.Context, r string) {
 }
@@ -0,0 +1,132 @@
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// Kind identifies the kind of declaration of an entry.
+type Kind int
+
+const (
+	// InvalidKind is the kind of an entry that was not collected.
+	InvalidKind Kind = iota
+	// PackageKind is a package annotated in its package doc comments.
+	PackageKind
+	// StructKind is a struct type, as in type Foo struct{...}.
+	StructKind
+	// InterfaceKind is an interface type, as in type Foo interface{...}.
+	InterfaceKind
+	// AliasKind is a type alias, as in type Foo = Bar.
+	AliasKind
+	// TypeKind is a type defined from a basic or named type, as in type ID string,
+	// or from any other type not covered by a more specific kind.
+	TypeKind
+	// FuncTypeKind is a function type, as in type Handler func(ctx context.Context).
+	FuncTypeKind
+	// MapKind is a map type, as in type Headers map[string]string.
+	MapKind
+	// SliceKind is a slice type, as in type IDs []ID.
+	SliceKind
+	// ArrayKind is an array type, as in type Hash [32]byte.
+	ArrayKind
+	// ChanKind is a channel type, as in type Events chan Event.
+	ChanKind
+	// PointerKind is a pointer type, as in type Ref *Node.
+	PointerKind
+	// FuncKind is a function.
+	FuncKind
+	// MethodKind is a method of a type or an interface.
+	MethodKind
+	// FieldKind is a field of a struct.
+	FieldKind
+	// ConstKind is a constant.
+	ConstKind
+	// VarKind is a variable.
+	VarKind
+)
+
+var kindNames = [...]string{
+	InvalidKind:   "invalid",
+	PackageKind:   "package",
+	StructKind:    "struct",
+	InterfaceKind: "interface",
+	AliasKind:     "alias",
+	TypeKind:      "type",
+	FuncTypeKind:  "func type",
+	MapKind:       "map",
+	SliceKind:     "slice",
+	ArrayKind:     "array",
+	ChanKind:      "chan",
+	PointerKind:   "pointer",
+	FuncKind:      "func",
+	MethodKind:    "method",
+	FieldKind:     "field",
+	ConstKind:     "const",
+	VarKind:       "var",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return kindNames[InvalidKind]
+	}
+	return kindNames[k]
+}
+
+// MarshalText encodes the kind as its name, as in "struct".
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind from its name.
+func (k *Kind) UnmarshalText(text []byte) error {
+	for kind, name := range kindNames {
+		if name == string(text) {
+			*k = Kind(kind)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown kind %q", text)
+}
+
+// In reports whether the kind is any of the kinds.
+func (k Kind) In(kinds ...Kind) bool {
+	for _, kind := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsType reports whether the kind is a type declaration.
+func (k Kind) IsType() bool {
+	return k.In(StructKind, InterfaceKind, AliasKind, TypeKind, FuncTypeKind, MapKind, SliceKind, ArrayKind, ChanKind, PointerKind)
+}
+
+// typeKind returns the kind of the type declared by the spec.
+func typeKind(typeSpec *ast.TypeSpec) Kind {
+	if typeSpec.Assign.IsValid() {
+		return AliasKind
+	}
+	switch t := typeSpec.Type.(type) {
+	case *ast.StructType:
+		return StructKind
+	case *ast.InterfaceType:
+		return InterfaceKind
+	case *ast.FuncType:
+		return FuncTypeKind
+	case *ast.MapType:
+		return MapKind
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return SliceKind
+		}
+		return ArrayKind
+	case *ast.ChanType:
+		return ChanKind
+	case *ast.StarExpr:
+		return PointerKind
+	}
+	return TypeKind
+}
@@ -0,0 +1,521 @@
+package annotation
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a single annotation comment, such as "// @Name(key=value)",
+// written in the default syntax.
+func Parse(comment string) (Annotation, error) {
+	return Syntax{}.Parse(comment)
+}
+
+// ParseAll parses the annotations found in a sequence of comments written
+// in the default syntax. See Syntax.ParseAll.
+func ParseAll(comments []string) ([]Annotation, error) {
+	return Syntax{}.ParseAll(comments)
+}
+
+// ParseError describes why an annotation could not be parsed.
+type ParseError struct {
+	Text   string // Text of the annotation
+	Line   int    // Line of the offending character, starting at 1
+	Column int    // Column of the offending character, starting at 1
+	Reason string // Why parsing failed
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Reason)
+}
+
+// ParseErrors is the list of errors found while parsing several annotations.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// parser is a small recursive descent parser for annotation comments.
+// String literals may be delimited by double quotes, single quotes or
+// backticks; separators inside them are kept verbatim.
+type parser struct {
+	src     string
+	pos     int
+	offset  int      // column of src within the comment, used in errors
+	resolve resolver // resolves references in unquoted values, if any
+}
+
+// resolver resolves a reference to a Go identifier, such as
+// http.StatusCreated, found in an unquoted value.
+type resolver func(ref string) (interface{}, bool)
+
+// parseAnnotation parses the text following the sigil of an annotation,
+// such as "Name(key=value)", written in the given grammar.
+func parseAnnotation(text string, offset int, grammar Grammar, resolve resolver) (an Annotation, err error) {
+
+	p := &parser{src: text, offset: offset, resolve: resolve}
+
+	switch grammar {
+	case MarkerGrammar:
+		an, err = p.parseMarker()
+	default:
+		an, err = p.parseBody()
+	}
+	if err != nil {
+		return Annotation{}, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return Annotation{}, p.errorf("unexpected %q after annotation", p.peek())
+	}
+
+	return an, nil
+}
+
+// isOpen reports whether the comment opens an argument list, list or map
+// that it does not close. Brackets inside string literals are ignored; a
+// quote only starts a literal at the beginning of a value.
+func isOpen(cmt string) bool {
+	depth := 0
+	var quote, last byte
+	for i := 0; i < len(cmt); i++ {
+		c := cmt[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case isQuote(c) && strings.IndexByte("=([{,:", last) >= 0:
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		}
+		if c != ' ' && c != '\t' {
+			last = c
+		}
+	}
+	return depth > 0 || quote != 0
+}
+
+// parseArgs parses a comma separated list of arguments until the end of the
+// input or an unbalanced closing parenthesis. Arguments are either named, as
+// in key=value, or positional.
+func parseArgs(value string) ([]Arg, error) {
+	p := &parser{src: value}
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return args, nil
+}
+
+func (p *parser) parseArgs() (args []Arg, err error) {
+	seen := make(map[string]bool)
+	for {
+		p.skipSpaces()
+		if p.eof() || p.peek() == ')' {
+			return args, nil
+		}
+
+		start := p.pos
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			if seen[key] {
+				p.pos = start
+				return nil, p.errorf("duplicate argument %q", key)
+			}
+			seen[key] = true
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if key == "" && p.pos == start {
+			return nil, p.errorf("missing argument")
+		}
+		args = append(args, Arg{Name: key, Value: value})
+
+		p.skipSpaces()
+		if p.eof() || p.peek() == ')' {
+			return args, nil
+		}
+		if p.peek() != ',' {
+			return nil, p.errorf("unexpected %q after argument", p.peek())
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) parseName() string {
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// parseKey consumes the "key=" prefix of a named argument. It returns an
+// empty key, consuming nothing, when the argument is positional.
+func (p *parser) parseKey() (string, error) {
+	start := p.pos
+	if !p.eof() && p.peek() == '=' {
+		return "", p.errorf("missing argument name")
+	}
+	key := p.parseName()
+	p.skipSpaces()
+	if key != "" && !p.eof() && isNameChar(p.peek()) {
+		if i := p.assignIndex(); i >= 0 {
+			name := strings.TrimSpace(p.src[start:i])
+			p.pos = start
+			return "", p.errorf("invalid argument name %q", name)
+		}
+	}
+	if key == "" || p.eof() || p.peek() != '=' {
+		p.pos = start
+		return "", nil
+	}
+	p.pos++
+	return key, nil
+}
+
+// assignIndex returns the index of the '=' following within the bare text
+// of the current argument, or -1.
+func (p *parser) assignIndex() int {
+	for i := p.pos; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '=':
+			return i
+		case ',', ')', '(', '[', ']', '{', '}', '@', '"', '\'', '`':
+			return -1
+		}
+	}
+	return -1
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return determineType(""), nil
+	}
+	switch c := p.peek(); {
+	case isQuote(c):
+		return p.parseString()
+	case c == '[' && !p.isSliceType():
+		return p.parseList()
+	case c == '{':
+		return p.parseMap()
+	case c == '@':
+		return p.parseNested()
+	}
+
+	value := determineType(p.parseBare())
+	if ref, ok := value.(string); ok && p.resolve != nil && isReference(ref) {
+		if resolved, ok := p.resolve(ref); ok {
+			log.Debugf("resolved the reference %s to %v", ref, resolved)
+			return resolved, nil
+		}
+	}
+	return value, nil
+}
+
+// isSliceType reports whether the value is a slice type expression, such
+// as []pkg.Response, instead of a list.
+func (p *parser) isSliceType() bool {
+	rest := p.src[p.pos:]
+	return strings.HasPrefix(rest, "[]") && len(rest) > 2 && !strings.ContainsRune(",)]} \t", rune(rest[2]))
+}
+
+// parseMap reads a braced object literal such as {timeout: 5s, retries: 3}.
+// Keys are names or quoted strings.
+func (p *parser) parseMap() (map[string]interface{}, error) {
+	start := p.pos
+	p.pos++
+
+	mp := make(map[string]interface{})
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			p.pos = start
+			return nil, p.errorf("missing closing brace")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return mp, nil
+		}
+
+		keyStart := p.pos
+		var key string
+		if isQuote(p.peek()) {
+			k, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		} else if key = p.parseName(); key == "" {
+			return nil, p.errorf("missing map key")
+		}
+		if _, ok := mp[key]; ok {
+			p.pos = keyStart
+			return nil, p.errorf("duplicate map key %q", key)
+		}
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != ':' {
+			return nil, p.errorf("missing ':' after map key %q", key)
+		}
+		p.pos++
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		mp[key] = value
+
+		p.skipSpaces()
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		} else if p.eof() || p.peek() != '}' {
+			p.pos = start
+			return nil, p.errorf("missing closing brace")
+		}
+	}
+}
+
+// parseNested reads an annotation used as a value, such as
+// @Response(code=200, type=pkg.Resp).
+func (p *parser) parseNested() (Annotation, error) {
+	p.pos++
+	return p.parseBody()
+}
+
+// parseBody reads an annotation name followed by an optional argument list.
+func (p *parser) parseBody() (Annotation, error) {
+
+	name := p.parseName()
+	if name == "" {
+		return Annotation{}, p.errorf("missing annotation name")
+	}
+
+	p.skipSpaces()
+	if p.eof() || p.peek() != '(' {
+		return newAnnotation(name, "", nil), nil
+	}
+	open := p.pos
+	p.pos++
+
+	start := p.pos
+	args, err := p.parseArgs()
+	if err != nil {
+		return Annotation{}, err
+	}
+	value := strings.TrimSpace(p.src[start:p.pos])
+
+	if p.eof() || p.peek() != ')' {
+		p.pos = open
+		return Annotation{}, p.errorf("missing closing parenthesis")
+	}
+	p.pos++
+
+	return newAnnotation(name, value, args), nil
+}
+
+// parseMarker reads a kubebuilder style marker such as "name:key=value,...",
+// "name=value" or "name". The marker name may contain colons; when more than
+// one argument follows the first "=", the segment after the last colon of
+// the name is taken as the first argument name.
+func (p *parser) parseMarker() (Annotation, error) {
+
+	start := p.pos
+	for !p.eof() && (isNameChar(p.peek()) || p.peek() == ':') {
+		p.pos++
+	}
+	name := strings.TrimSuffix(p.src[start:p.pos], ":")
+	if name == "" {
+		return Annotation{}, p.errorf("missing marker name")
+	}
+
+	if p.eof() || p.peek() != '=' {
+		return newAnnotation(name, "", nil), nil
+	}
+	p.pos++
+
+	valueStart := p.pos
+	first, err := p.parseValue()
+	if err != nil {
+		return Annotation{}, err
+	}
+	args := []Arg{{Value: first}}
+
+	p.skipSpaces()
+	if !p.eof() && p.peek() == ',' {
+		p.pos++
+		rest, err := p.parseArgs()
+		if err != nil {
+			return Annotation{}, err
+		}
+		args = append(args, rest...)
+	}
+	value := strings.TrimSpace(p.src[valueStart:p.pos])
+
+	if i := strings.LastIndex(name, ":"); len(args) > 1 && i >= 0 {
+		args[0].Name = name[i+1:]
+		value = strings.Join([]string{args[0].Name, value}, "=")
+		name = name[:i]
+	}
+
+	return newAnnotation(name, value, args), nil
+}
+
+// parseList reads a bracketed, comma separated list of values such as
+// [application/json, application/yaml].
+func (p *parser) parseList() ([]interface{}, error) {
+	start := p.pos
+	p.pos++
+
+	values := []interface{}{}
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			p.pos = start
+			return nil, p.errorf("missing closing bracket")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		valueStart := p.pos
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == valueStart {
+			return nil, p.errorf("missing list element")
+		}
+		values = append(values, value)
+
+		p.skipSpaces()
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		} else if p.eof() || p.peek() != ']' {
+			p.pos = start
+			return nil, p.errorf("missing closing bracket")
+		}
+	}
+}
+
+// parseBare reads an unquoted value up to the next top level comma or an
+// unbalanced closing parenthesis, bracket or brace.
+func (p *parser) parseBare() string {
+	start := p.pos
+	depth := 0
+	for ; !p.eof(); p.pos++ {
+		switch p.peek() {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return strings.TrimSpace(p.src[start:p.pos])
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return strings.TrimSpace(p.src[start:p.pos])
+			}
+		}
+	}
+	return strings.TrimSpace(p.src[start:p.pos])
+}
+
+// parseString reads a quoted string literal and returns its unquoted value.
+// Backtick strings are raw; the others accept Go escape sequences.
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	quote := p.peek()
+	p.pos++
+
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		if c == quote {
+			p.pos++
+			return sb.String(), nil
+		}
+		if c == '\\' && quote != '`' {
+			r, _, tail, err := strconv.UnquoteChar(p.src[p.pos:], quote)
+			if err != nil {
+				return "", p.errorf("invalid escape sequence in string literal")
+			}
+			sb.WriteRune(r)
+			p.pos = len(p.src) - len(tail)
+			continue
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	p.pos = start
+	return "", p.errorf("unterminated string literal")
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Text: p.src, Line: 1, Column: p.offset + p.pos + 1, Reason: fmt.Sprintf(format, args...)}
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// isReference reports whether the value looks like a Go identifier, possibly
+// qualified by a package name or import path, as in pkg.Name.
+func isReference(value string) bool {
+	qualifier, name := "", value
+	if i := strings.LastIndex(value, "."); i >= 0 {
+		qualifier, name = value[:i], value[i+1:]
+		if qualifier == "" || strings.Trim(qualifier, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_./-") != "" {
+			return false
+		}
+	}
+	if name == "" || !token.IsIdentifier(name) {
+		return false
+	}
+	return true
+}
+
+func isQuote(c byte) bool {
+	return c == '"' || c == '\'' || c == '`'
+}
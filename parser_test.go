@@ -0,0 +1,186 @@
+package annotation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      *ParseError
+	}{
+		{"Test1", "// @RestRequest(code=201)", "RestRequest", nil},
+		{"Test2", "// @Invoke", "Invoke", nil},
+		{"Test3", "// @MyAnnotation(code=201", "", &ParseError{Line: 1, Column: 17, Reason: "missing closing parenthesis"}},
+		{"Test4", `// @RestResponse(description="foo)`, "", &ParseError{Line: 1, Column: 30, Reason: "unterminated string literal"}},
+		{"Test5", "// @A Param query foo", "", &ParseError{Line: 1, Column: 7, Reason: "unexpected 'P' after annotation"}},
+		{"Test6", "// FooFunc Lorem ipsum", "", &ParseError{Line: 1, Column: 1, Reason: "the comment is not an annotation"}},
+		{"Test7", "// @(code=201)", "", &ParseError{Line: 1, Column: 5, Reason: "missing annotation name"}},
+		{"Test8", "// @Path(, method=GET)", "", &ParseError{Line: 1, Column: 10, Reason: "missing argument"}},
+		{"Test9", "// @Config(defaults={timeout 5s})", "", &ParseError{Line: 1, Column: 30, Reason: `missing ':' after map key "timeout"`}},
+		{"Test10", "// @Foo(=1)", "", &ParseError{Line: 1, Column: 9, Reason: "missing argument name"}},
+		{"Test11", "// @Foo(a=1, my key=1)", "", &ParseError{Line: 1, Column: 14, Reason: `invalid argument name "my key"`}},
+		{"Test12", "// @Foo(Hello world)", "Foo", nil},
+		{"Test13", `// @Foo(a="x"y)`, "", &ParseError{Line: 1, Column: 14, Reason: `unexpected 'y' after argument`}},
+		{"Test14", "// @Foo(a=[1] b=2)", "", &ParseError{Line: 1, Column: 15, Reason: `unexpected 'b' after argument`}},
+		{"Test15", "// @Foo(a=1, a=2)", "", &ParseError{Line: 1, Column: 14, Reason: `duplicate argument "a"`}},
+		{"Test16", "// @Foo(m={x:1, x:2})", "", &ParseError{Line: 1, Column: 17, Reason: `duplicate map key "x"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.err == nil {
+				if err != nil || got.Name != tt.expected {
+					t.Errorf("Parse() = %v, %v, want %v", got.Name, err, tt.expected)
+				}
+				return
+			}
+			perr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if perr.Line != tt.err.Line || perr.Column != tt.err.Column || perr.Reason != tt.err.Reason {
+				t.Errorf("Parse() error = %v, want %v", perr, tt.err)
+			}
+		})
+	}
+}
+
+func TestParse_value(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		args     []string
+	}{
+		{"Test1", "// @RestAction ( type=action, data=123 )", "type=action, data=123", []string{"type", "data"}},
+		{"Test2", "// @RestAction(data=123,type=action,xpto=456)", "data=123,type=action,xpto=456", []string{"data", "type", "xpto"}},
+		{"Test3", `// @RestResponse(description="foo, bar",  code=201)`, `description="foo, bar",  code=201`, []string{"description", "code"}},
+		{"Test4", "// @Invoke", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.RawValue() != tt.expected {
+				t.Errorf("Parse().RawValue() = %q, want %q", got.RawValue(), tt.expected)
+			}
+			var args []string
+			for _, arg := range got.Args {
+				args = append(args, arg.Name)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Parse().Args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	input := []string{
+		"// FooFunc Lorem ipsum",
+		"// @RestRouter(path=/, method=POST)",
+		"// @RestQueryParam(",
+		"//   name=foo,",
+		"//   type=[bool)",
+		"// @MyAnnotation(code=201",
+		"// @Invoke",
+	}
+
+	ans, err := ParseAll(input)
+
+	var names []string
+	for _, an := range ans {
+		names = append(names, an.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"RestRouter", "Invoke"}) {
+		t.Errorf("ParseAll() = %v", names)
+	}
+
+	errs, ok := err.(ParseErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("ParseAll() error = %v, want two errors", err)
+	}
+	if errs[0].Line != 5 || errs[0].Column != 11 || errs[0].Reason != "missing closing bracket" {
+		t.Errorf("ParseAll() errors[0] = %v", errs[0])
+	}
+	if errs[1].Line != 6 || errs[1].Column != 17 {
+		t.Errorf("ParseAll() errors[1] = %v", errs[1])
+	}
+
+	ans, err = ParseAll([]string{"@A(x=1,", "  y=2)", "Lorem ipsum", "@B"})
+	if err != nil || len(ans) != 2 || ans[0].Map["y"] != 2 || ans[1].Name != "B" {
+		t.Errorf("ParseAll() = %v, %v", ans, err)
+	}
+}
+
+func TestSyntax_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"Test1", "// @RestResponse(code=201, type=github.com/americanas-go/inject/examples/simple.Response, description=tiam sed efficitur purus at lacinia magna)", true},
+		{"Test2", "// @RestRequest(code=201)", true},
+		{"Test3", "// @RestAction (type=action, data=123)", true},
+		{"Test4", "// @RestAction (type=action, data=123)", true},
+		{"Test5", "// @RestAction ( type=action, data=123)", true},
+		{"Test6", "// @RestAction ( type=action, data=123 )", true},
+		{"Test7", "// @RestAction (type=action,data=123, xpto=456)", true},
+		{"Test8", "// @RestRequest(code=201)", true},
+		{"Test9", "//@RestAction (type=action, data=123)", false},
+		{"Test10", "//@RestAction (type=action, data=123)", false},
+		{"Test11", "//@RestAction ( type=action, data=123)", false},
+		{"Test12", "//@RestAction ( type=action, data=123 )", false},
+		{"Test13", "//@RestAction (type=action,data=123, xpto=456)", false},
+		{"Test14", "// FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit", false},
+		{"Test15", "//FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit", false},
+		{"Test16", "// @MyAnnotation(code=201)", true},
+		{"Test16", "// @MyAnnotation(code=201", false},
+		{"Test16", "// @A Param query foo bool true tiam sed efficitur purus", false},
+		{"Test16", "// @Invoke", true},
+		{"Test17", `// @RestResponse(description="foo, bar (baz)")`, true},
+		{"Test18", "// @RestRouter(path=/a?x=1)", true},
+		{"Test19", `// @RestResponse(description="foo, bar (baz))`, false},
+		{"Test20", `// @RestResponse(description='it\'s')`, true},
+		{"Test21", "// @Inject(FooService)", true},
+		{"Test22", `// @Path("/users", method=GET)`, true},
+		{"Test23", "// @Path(, method=GET)", false},
+		{"Test24", "// @RestConsume(types=[application/json, application/yaml])", true},
+		{"Test25", "// @RestConsume(types=[application/json, application/yaml)", false},
+		{"Test26", "// @RestEndpoint(path=/foo, responses=[@Response(code=200, type=pkg.Resp), @Response(code=404)])", true},
+		{"Test27", "// @RestEndpoint(path=/foo, responses=[@Response(code=200, type=pkg.Resp])", false},
+		{"Test28", "@RestRequest(code=201)", true},
+		{"Test29", "FooFunc Lorem ipsum", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if _, err := (Syntax{}).Parse(tt.input); (err == nil) != tt.expected {
+				t.Errorf("Parse() error = %v, want valid %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSyntax_Parse_block(t *testing.T) {
+	s := Syntax{BlockComments: true}
+
+	if _, err := s.Parse("/*\n * @Path(\n *   \"/users\")\n */"); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	_, err := s.Parse("/*\n * @Path(\n *   \"/users)\n */")
+	perr, ok := err.(*ParseError)
+	if !ok || perr.Line != 3 || perr.Column != 6 {
+		t.Errorf("Parse() error = %v, want 3:6", err)
+	}
+}
@@ -0,0 +1,125 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// commentSource describes where the comments being parsed were found.
+type commentSource struct {
+	pkg       *packages.Package
+	file      *ast.File
+	positions []token.Position // Position of each comment
+}
+
+// resolveConst resolves a reference to a constant, such as
+// http.StatusCreated or defaultTimeout, into its value.
+func (s commentSource) resolveConst(ref string) (interface{}, bool) {
+	c, ok := s.lookup(ref).(*types.Const)
+	if !ok {
+		return nil, false
+	}
+	return constantValue(c), true
+}
+
+// lookup finds the object named by a reference. Unqualified names are looked
+// up in the package scope; qualified ones in the package imported by the
+// file under that name or, failing that, in the loaded package with that
+// import path.
+func (s commentSource) lookup(ref string) types.Object {
+	if s.pkg == nil || s.pkg.Types == nil {
+		return nil
+	}
+
+	i := strings.LastIndex(ref, ".")
+	if i < 0 {
+		return s.pkg.Types.Scope().Lookup(ref)
+	}
+
+	pkg := s.importedPackage(ref[:i])
+	if pkg == nil {
+		pkg = findPackage(s.pkg, ref[:i])
+	}
+	if pkg == nil || pkg.Types == nil {
+		return nil
+	}
+	return pkg.Types.Scope().Lookup(ref[i+1:])
+}
+
+// importedPackage returns the package imported by the file under the name.
+func (s commentSource) importedPackage(name string) *packages.Package {
+	if s.file == nil {
+		return nil
+	}
+	for _, imp := range s.file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		pkg, ok := s.pkg.Imports[path]
+		if !ok {
+			continue
+		}
+		if (imp.Name != nil && imp.Name.Name == name) || (imp.Name == nil && pkg.Name == name) {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// findPackage returns the package with the import path among the package
+// and its dependencies.
+func findPackage(root *packages.Package, path string) *packages.Package {
+	seen := make(map[*packages.Package]bool)
+	queue := []*packages.Package{root}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if pkg.PkgPath == path {
+			return pkg
+		}
+		for _, imp := range pkg.Imports {
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
+// constantValue converts the value of a constant into the Go value an
+// equivalent literal would have.
+func constantValue(c *types.Const) interface{} {
+	v := c.Val()
+	switch v.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Int:
+		if i, ok := constant.Int64Val(v); ok {
+			if isDuration(c.Type()) {
+				return time.Duration(i)
+			}
+			return int(i)
+		}
+	case constant.Float:
+		if f, ok := constant.Float64Val(v); ok {
+			return f
+		}
+	}
+	return v.ExactString()
+}
+
+func isDuration(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration"
+}
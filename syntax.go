@@ -0,0 +1,208 @@
+package annotation
+
+import (
+	"strings"
+)
+
+// Grammar identifies how an annotation is written after its sigil.
+type Grammar int
+
+const (
+	// AnnotationGrammar is the default grammar, as in @Name(key=value, ...).
+	AnnotationGrammar Grammar = iota
+	// MarkerGrammar is the kubebuilder style grammar, as in +name:key=value,...
+	MarkerGrammar
+)
+
+// Syntax describes how annotations are marked in comments. The zero value
+// matches the default "// @Name(...)" form.
+type Syntax struct {
+	Sigil         string  // Sigil starting an annotation, "@" by default or "+" for markers
+	OptionalSpace bool    // Whether "//@Name" is accepted besides "// @Name"
+	BlockComments bool    // Whether annotations inside /* */ comments are collected
+	Grammar       Grammar // Grammar of the annotation after the sigil
+}
+
+// MarkerSyntax returns the syntax of kubebuilder style markers, such as
+// "// +kubebuilder:validation:Minimum=1".
+func MarkerSyntax() Syntax {
+	return Syntax{Sigil: "+", OptionalSpace: true, Grammar: MarkerGrammar}
+}
+
+// Parse parses a single annotation comment, which may span several lines
+// when it is a block comment. The comment markers are optional, as in
+// "@Name(...)".
+func (s Syntax) Parse(comment string) (Annotation, error) {
+	lines := s.join(s.lines([]string{comment}))
+	if len(lines) == 0 || !s.isAnnotation(lines[0].lines[0]) {
+		return Annotation{}, &ParseError{Text: comment, Line: 1, Column: 1, Reason: "the comment is not an annotation"}
+	}
+	if len(lines) > 1 {
+		l := lines[1].lines[0]
+		return Annotation{}, &ParseError{Text: comment, Line: l.row + 1, Column: l.col + 1, Reason: "unexpected text after annotation"}
+	}
+	return s.parse(lines[0], nil)
+}
+
+// ParseAll parses the annotations found in a sequence of comments, or of
+// lines without comment markers, joining annotations whose arguments continue
+// on the following lines. Comments that are not annotations are skipped. When
+// some annotations are malformed, the valid ones are returned along with
+// ParseErrors describing the others.
+func (s Syntax) ParseAll(comments []string) (ans []Annotation, err error) {
+	var errs ParseErrors
+	for _, l := range s.join(s.lines(comments)) {
+		if !s.isAnnotation(l.lines[0]) {
+			continue
+		}
+		an, err := s.parse(l, nil)
+		if err != nil {
+			errs = append(errs, err.(*ParseError))
+			continue
+		}
+		ans = append(ans, an)
+	}
+	if len(errs) > 0 {
+		return ans, errs
+	}
+	return ans, nil
+}
+
+func (s Syntax) sigil() string {
+	if s.Sigil != "" {
+		return s.Sigil
+	}
+	if s.Grammar == MarkerGrammar {
+		return "+"
+	}
+	return "@"
+}
+
+// commentLine is a single line of comment text without its comment markers.
+type commentLine struct {
+	text  string // Text of the line
+	block bool   // Whether the line belongs to a block comment or to text without comment markers
+	cmt   int    // Index of the comment the line belongs to
+	line  int    // Line of the text within its comment, starting at 0
+	row   int    // Line of the text across all comments, starting at 0
+	col   int    // Column of the text within its line, starting at 0
+}
+
+// annotationLine is a logical annotation line, joined from one or more
+// comment lines; starts holds the offset of each of them within text.
+type annotationLine struct {
+	text   string
+	lines  []commentLine
+	starts []int
+}
+
+// position maps an offset within the text to its comment line and column.
+func (l annotationLine) position(offset int) (commentLine, int) {
+	i := len(l.starts) - 1
+	for i > 0 && l.starts[i] > offset {
+		i--
+	}
+	return l.lines[i], l.lines[i].col + offset - l.starts[i]
+}
+
+// lines returns the text of the comments without their comment markers, one
+// element per line. Block comments are skipped unless enabled. Text without
+// comment markers, such as annotations read from other sources, is taken as
+// is, one annotation per line.
+func (s Syntax) lines(cmts []string) (lines []commentLine) {
+	var row int
+	for i, cmt := range cmts {
+		base := row
+		row += strings.Count(cmt, "\n") + 1
+		if strings.HasPrefix(cmt, "//") {
+			lines = append(lines, commentLine{text: strings.TrimPrefix(cmt, "//"), cmt: i, row: base, col: len("//")})
+			continue
+		}
+		block := strings.HasPrefix(cmt, "/*")
+		if block && !s.BlockComments {
+			continue
+		}
+		body := cmt
+		if block {
+			body = strings.TrimSuffix(cmt, "*/")
+		}
+		for j, raw := range strings.Split(body, "\n") {
+			if j == 0 && block {
+				raw = strings.Replace(raw, "/*", "  ", 1)
+			}
+			rest := strings.TrimLeft(raw, " \t")
+			if block && strings.HasPrefix(rest, "*") {
+				rest = strings.TrimLeft(rest[1:], " \t")
+			}
+			if text := strings.TrimRight(rest, " \t"); text != "" {
+				col := len(raw) - len(rest)
+				lines = append(lines, commentLine{text: text, block: true, cmt: i, line: j, row: base + j, col: col})
+			}
+		}
+	}
+	return lines
+}
+
+// trim returns the text following the sigil of an annotation line and its
+// offset within the line text, or false when the line is not an annotation.
+func (s Syntax) trim(l commentLine) (string, int, bool) {
+	text := l.text
+	switch {
+	case s.OptionalSpace:
+		text = strings.TrimLeft(text, " \t")
+	case !l.block:
+		if !strings.HasPrefix(text, " ") {
+			return "", 0, false
+		}
+		text = text[1:]
+	}
+	if !strings.HasPrefix(text, s.sigil()) {
+		return "", 0, false
+	}
+	text = strings.TrimPrefix(text, s.sigil())
+	return text, len(l.text) - len(text), true
+}
+
+func (s Syntax) isAnnotation(l commentLine) bool {
+	_, _, ok := s.trim(l)
+	return ok
+}
+
+// parse parses an annotation line, reporting errors at their position
+// within the comments.
+func (s Syntax) parse(l annotationLine, resolve resolver) (Annotation, error) {
+	_, offset, _ := s.trim(l.lines[0])
+	an, err := parseAnnotation(l.text[offset:], offset, s.Grammar, resolve)
+	if err != nil {
+		perr := err.(*ParseError)
+		cl, col := l.position(perr.Column - 1)
+		perr.Text = strings.TrimSpace(l.text)
+		perr.Line = cl.row + 1
+		perr.Column = col + 1
+		return Annotation{}, perr
+	}
+	return an, nil
+}
+
+// join merges annotations whose arguments continue on the following lines
+// into a single logical line. The continuation ends at the line that closes
+// the argument list, or before the next line that starts an annotation of
+// its own.
+func (s Syntax) join(lines []commentLine) (joined []annotationLine) {
+	for i := 0; i < len(lines); i++ {
+		l := annotationLine{text: lines[i].text, lines: lines[i : i+1], starts: []int{0}}
+		if s.isAnnotation(lines[i]) {
+			for isOpen(l.text) && i+1 < len(lines) && !s.isAnnotation(lines[i+1]) {
+				i++
+				next := strings.TrimLeft(lines[i].text, " \t")
+				cl := lines[i]
+				cl.col += len(cl.text) - len(next)
+				l.text = strings.Join([]string{l.text, next}, " ")
+				l.lines = append(l.lines[:len(l.lines):len(l.lines)], cl)
+				l.starts = append(l.starts, len(l.text)-len(next))
+			}
+		}
+		joined = append(joined, l)
+	}
+	return joined
+}
@@ -0,0 +1,19 @@
+package consts
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	defaultName    = "orders"
+	ratio          = 0.5
+	enabled        = true
+)
+
+// Handler Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @RestResponse(code=http.StatusCreated, method=http.MethodPost, timeout=defaultTimeout)
+// @Config(name=defaultName, ratio=ratio, enabled=enabled, raw="defaultName", unknown=missing, path=net/http.StatusOK)
+func Handler(w http.ResponseWriter, r *http.Request) {
+}
@@ -0,0 +1,25 @@
+package fields
+
+import "time"
+
+type Base struct {
+	ID int
+}
+
+// User Lorem ipsum dolor sit amet, consectetur adipiscing elit
+type User struct {
+	*Base
+	// Name is the name of the user.
+	// @Column(name=name)
+	// @Validate(min=1)
+	Name string `json:"name"`
+	// @Column(name=created_at)
+	CreatedAt, UpdatedAt time.Time
+	Age                  int `json:"age"` // @Validate(min=18)
+	ignored              bool
+	*Audit[time.Time]
+}
+
+type Audit[T any] struct {
+	At T
+}
@@ -0,0 +1,21 @@
+package generics
+
+type Entity interface {
+	ID() string
+}
+
+// @Repository
+type Repo[T Entity, K comparable] struct {
+	items map[K]T
+}
+
+// @Handler
+func (r *Repo[E, K]) Save(entity E) {}
+
+// @Handler
+func Map[T any, U ~int | ~string](items []T, fn func(T) U) []U {
+	return nil
+}
+
+// @Handler
+func Store[T Entity](entity T) {}
@@ -0,0 +1,11 @@
+package iface
+
+import "net/http"
+
+// Finder looks users up.
+type Finder interface {
+	// @Get(path=/{id}, code=http.StatusOK)
+	Find(id int) (*User, error)
+	// @Get(path=/
+	FindAll() ([]User, error)
+}
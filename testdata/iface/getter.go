@@ -0,0 +1,7 @@
+package iface
+
+// Getter gets a value.
+type Getter[T any] interface {
+	// @Get(path=/value)
+	Value() T
+}
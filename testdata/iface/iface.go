@@ -0,0 +1,30 @@
+package iface
+
+import (
+	"io"
+
+	"github.com/americanas-go/annotation/testdata/remote"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+// UserClient is the client of the users API.
+// @HttpClient(base=/users)
+type UserClient interface {
+	Finder
+	Getter[User]
+	io.Closer
+	remote.Pinger
+
+	// @Post(path=/)
+	Create(user User) error
+	Ping() error
+}
+
+// @AdminClient
+type Admin interface {
+	Finder
+}
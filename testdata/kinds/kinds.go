@@ -0,0 +1,55 @@
+// @Module
+package kinds
+
+import (
+	"context"
+	"io"
+)
+
+// @Model
+type User struct {
+	// @Column
+	ID ID
+}
+
+// @Model
+type Reader interface {
+	// @Model
+	Read() error
+}
+
+// @Model
+type Closer = io.Closer
+
+// @Model
+type ID string
+
+// @Model
+type Handler func(ctx context.Context) error
+
+// @Model
+func (h Handler) Serve() {}
+
+// @Model
+type Headers map[string]string
+
+// @Model
+type IDs []ID
+
+// @Model
+type Hash [32]byte
+
+// @Model
+func New() {}
+
+// @Model
+const Default ID = "default"
+
+// @Model
+var Current ID
+
+// @Model
+type Events chan ID
+
+// @Model
+type Ref *User
@@ -0,0 +1,3 @@
+// Package pkgdoc holds the orders domain.
+// @Module(name=orders)
+package pkgdoc
@@ -0,0 +1,7 @@
+// @Layer(domain)
+package pkgdoc
+
+// @Entity
+type Order struct {
+	ID int
+}
@@ -0,0 +1,13 @@
+package qualified
+
+import (
+	"context"
+	nethttp "net/http"
+
+	"github.com/americanas-go/annotation/testdata/typeref/model"
+)
+
+// @Handler
+func Handle(ctx context.Context, req *nethttp.Request, users []model.Response, headers map[string]*model.Response, opts ...string) (model.Error, error) {
+	return model.Error{}, nil
+}
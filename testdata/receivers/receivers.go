@@ -0,0 +1,29 @@
+package receivers
+
+// @Service
+type Service struct{}
+
+// @Handler(path=/value)
+func (s Service) Value() {}
+
+// @Handler(path=/pointer)
+func (s *Service) Pointer() {}
+
+// @Cache
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+// @Handler(path=/get)
+func (c *Cache[K, V]) Get(key K) V {
+	return c.items[key]
+}
+
+// @Repository
+type Box[T any] struct{}
+
+// @Handler(path=/unbox)
+func (Box[T]) Unbox() {}
+
+// @Handler(path=/free)
+func Free() {}
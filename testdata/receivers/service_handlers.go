@@ -0,0 +1,4 @@
+package receivers
+
+// @Handler(path=/other)
+func (s *Service) Other() {}
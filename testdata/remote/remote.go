@@ -0,0 +1,7 @@
+package remote
+
+// Pinger checks the health of a remote service.
+type Pinger interface {
+	// @Get(path=/health)
+	Health() error
+}
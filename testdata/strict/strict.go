@@ -0,0 +1,14 @@
+package strict
+
+// FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @MyAnnotation(code=201
+// @Invoke
+func FooFunc() {
+}
+
+// BarFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @RestQueryParam(
+// name=foo,
+// description="tiam sed efficitur purus)
+func BarFunc() {
+}
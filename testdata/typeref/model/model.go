@@ -0,0 +1,11 @@
+package model
+
+// Response Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @Model
+type Response struct {
+	Message string
+}
+
+type Error struct {
+	Code int
+}
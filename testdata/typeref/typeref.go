@@ -0,0 +1,33 @@
+package typeref
+
+import (
+	"github.com/americanas-go/annotation/testdata/typeref/model"
+)
+
+// FooFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @RestResponse(code=200, type=model.Response)
+// @RestResponse(code=404, type=*github.com/americanas-go/annotation/testdata/typeref/model.Error)
+// @RestRequest(types=[Request, []model.Error])
+func FooFunc(r *Request) (*model.Response, error) {
+	return nil, nil
+}
+
+// BarFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @RestResponse(code=500, type=model.Missing)
+func BarFunc() {
+}
+
+type Request struct {
+}
+
+// BazFunc Lorem ipsum dolor sit amet, consectetur adipiscing elit
+// @RestEndpoint(path=/baz, responses=[@Response(code=200, type=model.Response), @Response(code=404, type=model.Nope)])
+func BazFunc() {
+}
+
+// @Enum(type=Missing)
+const (
+	A = iota
+	B
+	C
+)
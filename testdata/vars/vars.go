@@ -0,0 +1,37 @@
+package vars
+
+import (
+	"net/http"
+	"time"
+)
+
+type Color int
+
+// @Enum
+const (
+	// @Value(name=red)
+	Red   Color = iota
+	Green       // @Value(name=green)
+	Blue
+)
+
+// @Config(key=timeout)
+const Timeout = 5 * time.Second
+
+var (
+	// @Route(path=/users)
+	handler http.HandlerFunc = nil
+	ignored                  = 1
+)
+
+// @Flag
+var verbose, debug bool
+
+// @Enum(name=size
+const (
+	// Small is small.
+	Small = iota
+	Large
+)
+
+var retries = 3 // @Config(key=retries)
@@ -0,0 +1,168 @@
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// TypeRef is an annotation value referencing a Go type, resolved from
+// attributes registered with WithTypeRefs.
+type TypeRef struct {
+	Ref     string     // Reference as written, e.g. *pkg.Response
+	Package string     // Import path of the package declaring the type
+	Name    string     // Name of the type
+	Type    types.Type `yaml:"-" json:"-"` // Resolved type
+}
+
+// String returns the fully qualified type, e.g. *github.com/foo/pkg.Response.
+func (t TypeRef) String() string {
+	return types.TypeString(t.Type, nil)
+}
+
+// EntryOf returns the collected entry declaring the referenced type.
+func (c *Collector) EntryOf(ref TypeRef) (Entry, bool) {
+	for _, entry := range c.Entries() {
+		if entry.Path != ref.Package {
+			continue
+		}
+		if entry.Kind.IsType() && (entry.Struct == ref.Name || entry.Interface == ref.Name) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// resolveTypeRefs replaces the values of the attributes registered as type
+// references with their TypeRef, reporting the ones that can't be resolved.
+// The annotations of a comment group are shared by the entries it documents,
+// so each group is resolved once.
+func (c *Collector) resolveTypeRefs() {
+	resolved := make(map[*ast.CommentGroup]bool)
+	for _, entry := range c.entries {
+		for _, group := range entry.docs {
+			if group == nil || resolved[group] {
+				continue
+			}
+			resolved[group] = true
+			for _, an := range c.parsed[group] {
+				c.resolveAnnotationTypeRefs(entry.src, an, an.Position)
+			}
+		}
+	}
+}
+
+// resolveAnnotationTypeRefs resolves the type references of the annotation
+// and of the annotations nested in its arguments, which are reported at the
+// position of the top-level annotation.
+func (c *Collector) resolveAnnotationTypeRefs(src commentSource, an Annotation, pos token.Position) {
+	for _, attr := range c.typeRefs[an.Name] {
+		value, ok := an.Map[attr]
+		if !ok {
+			continue
+		}
+		resolved, err := c.resolveTypeValue(src, value)
+		if err != nil {
+			c.diagnostics = append(c.diagnostics, Diagnostic{
+				Position: pos,
+				Message:  fmt.Sprintf("invalid type reference in %s%s(%s): %s", c.syntax.sigil(), an.Name, attr, err.Error()),
+			})
+			log.Warnf("the type reference %s of the annotation %s at %s could not be resolved. %s", attr, an.Name, pos, err.Error())
+			continue
+		}
+		an.Map[attr] = resolved
+		for i, arg := range an.Args {
+			if arg.Name == attr {
+				an.Args[i].Value = resolved
+			}
+		}
+	}
+
+	for _, arg := range an.Args {
+		c.resolveNestedTypeRefs(src, arg.Value, pos)
+	}
+}
+
+// resolveNestedTypeRefs resolves the type references of the annotations found
+// in the value, looking into lists and maps.
+func (c *Collector) resolveNestedTypeRefs(src commentSource, value interface{}, pos token.Position) {
+	switch v := value.(type) {
+	case Annotation:
+		c.resolveAnnotationTypeRefs(src, v, pos)
+	case []interface{}:
+		for _, item := range v {
+			c.resolveNestedTypeRefs(src, item, pos)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			c.resolveNestedTypeRefs(src, item, pos)
+		}
+	}
+}
+
+// resolveTypeValue resolves a type reference or a list of them.
+func (c *Collector) resolveTypeValue(src commentSource, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return c.resolveType(src, v)
+	case []interface{}:
+		refs := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			ref, err := c.resolveTypeValue(src, item)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, ref)
+		}
+		return refs, nil
+	case TypeRef:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%v is not a type reference", value)
+}
+
+// resolveType resolves a reference such as pkg.Response, *Response or
+// []github.com/foo/pkg.Response. Package names are looked up in the imports
+// of the file; import paths among all the loaded packages.
+func (c *Collector) resolveType(src commentSource, ref string) (TypeRef, error) {
+	name := strings.TrimSpace(ref)
+	var wrappers []string
+	for strings.HasPrefix(name, "*") || strings.HasPrefix(name, "[]") {
+		prefix := name[:1]
+		if prefix != "*" {
+			prefix = "[]"
+		}
+		wrappers = append(wrappers, prefix)
+		name = strings.TrimPrefix(name, prefix)
+	}
+
+	obj := src.lookup(name)
+	if i := strings.LastIndex(name, "."); obj == nil && i >= 0 {
+		if pkg, ok := c.loaded[name[:i]]; ok && pkg.Types != nil {
+			obj = pkg.Types.Scope().Lookup(name[i+1:])
+		}
+	}
+
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return TypeRef{}, fmt.Errorf("unknown type %q", ref)
+	}
+
+	t := tn.Type()
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		if wrappers[i] == "*" {
+			t = types.NewPointer(t)
+		} else {
+			t = types.NewSlice(t)
+		}
+	}
+
+	var path string
+	if tn.Pkg() != nil {
+		path = tn.Pkg().Path()
+	}
+
+	return TypeRef{Ref: ref, Package: path, Name: tn.Name(), Type: t}, nil
+}